@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tfe "github.com/hashicorp/go-tfe"
+)
+
+const (
+	// backupVersion is version of backup directory layout
+	backupVersion = 1
+	// backupManifestFile is filename of manifest in backup directory
+	backupManifestFile = "manifest.json"
+	// backupFileMode is file mode of backup files, which may contain secrets not marked as sensitive
+	backupFileMode os.FileMode = 0600
+)
+
+// BackupManifest describe contents of backup directory
+type BackupManifest struct {
+	Version      int            `json:"version"`
+	Organization string         `json:"organization"`
+	ExportedAt   time.Time      `json:"exported_at"`
+	Workspaces   []*BackupEntry `json:"workspaces"`
+	VariableSets []*BackupEntry `json:"variable_sets,omitempty"`
+}
+
+// BackupEntry is metadata of variables file of a workspace or variable set
+type BackupEntry struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	File      string   `json:"file"`
+	Variables int      `json:"variables"`
+	Sensitive []string `json:"sensitive_keys,omitempty"` // keys whose values are not exported
+}
+
+// newBackupEntry build entry of variables written into file
+func newBackupEntry(id string, name string, file string, variables []*tfe.Variable) *BackupEntry {
+	entry := &BackupEntry{
+		ID:        id,
+		Name:      name,
+		File:      file,
+		Variables: len(variables),
+	}
+	for _, v := range variables {
+		if v.Sensitive {
+			entry.Sensitive = append(entry.Sensitive, v.Key)
+		}
+	}
+
+	return entry
+}
+
+// writeBackupFile write value as indented JSON into dir/file
+func writeBackupFile(dir string, file string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	filename := filepath.Join(dir, file)
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, append(data, '\n'), backupFileMode)
+}
+
+// readBackupManifest read manifest of backup directory
+func readBackupManifest(dir string) (*BackupManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, backupManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &BackupManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if manifest.Version != backupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", manifest.Version)
+	}
+
+	return manifest, nil
+}
+
+// readBackupVariables read variables file of entry
+func readBackupVariables(dir string, entry *BackupEntry) ([]*tfe.Variable, error) {
+	data, err := os.ReadFile(filepath.Join(dir, entry.File))
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := []*VariableOutput{}
+	if err := json.Unmarshal(data, &outputs); err != nil {
+		return nil, fmt.Errorf("invalid variables file %s: %w", entry.File, err)
+	}
+
+	variables := []*tfe.Variable{}
+	for _, o := range outputs {
+		variables = append(variables, o.variable())
+	}
+
+	return variables, nil
+}
+
+// variable return tfe variable with attributes of output
+func (o *VariableOutput) variable() *tfe.Variable {
+	return &tfe.Variable{
+		ID:          o.ID,
+		Key:         o.Key,
+		Value:       o.Value,
+		Description: o.Description,
+		Category:    tfe.CategoryType(o.Category),
+		HCL:         o.HCL,
+		Sensitive:   o.Sensitive,
+	}
+}
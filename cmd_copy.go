@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	CONFLICT_SKIP      = "skip"
+	CONFLICT_OVERWRITE = "overwrite"
+	CONFLICT_FAIL      = "fail"
+)
+
+type CopyOption struct {
+	from        string
+	to          string
+	filter      *VariableFilter
+	conflict    string
+	dryRun      bool
+	varFile     string
+	envFile     string
+	autoApprove bool
+	reveal      bool
+	in          io.Reader
+	out         io.Writer
+}
+
+func NewCopyOption(c *cli.Context) *CopyOption {
+	var opt = &CopyOption{}
+
+	opt.from = c.String("from")
+	opt.to = c.String("to")
+	opt.filter = NewVariableFilter(c, c.StringSlice("variable"))
+	opt.conflict = c.String("conflict")
+	opt.dryRun = c.Bool("dry-run")
+	opt.varFile = c.String("var-file")
+	opt.envFile = c.String("env-file")
+	opt.autoApprove = c.Bool("auto-approve")
+	opt.reveal = c.Bool("reveal")
+
+	opt.in = os.Stdin
+	opt.out = os.Stdout
+
+	return opt
+}
+
+// SensitiveValues is values of sensitive variables read from local files by category
+type SensitiveValues map[tfe.CategoryType]map[string]string
+
+// NewSensitiveValues read values of terraform variables from var-file and env variables from dotenv file
+// files not specified are ignored
+func NewSensitiveValues(varFile string, envFile string) (SensitiveValues, error) {
+	values := SensitiveValues{
+		tfe.CategoryTerraform: map[string]string{},
+		tfe.CategoryEnv:       map[string]string{},
+	}
+
+	if varFile != "" {
+		if _, err := os.Stat(varFile); err != nil {
+			log.Error().Err(err).Msgf("cannot read var-file: %s", varFile)
+			return nil, err
+		}
+		vf, err := NewTfvarsFile(varFile)
+		if err != nil {
+			log.Error().Err(err).Msgf("failed to parse var-file: %s", varFile)
+			return nil, err
+		}
+		for _, v := range vf.vars {
+			values[tfe.CategoryTerraform][v.Key] = v.Value
+		}
+	}
+
+	if envFile != "" {
+		src, err := readDecryptedFile(envFile)
+		if err != nil {
+			log.Error().Err(err).Msgf("cannot read env-file: %s", envFile)
+			return nil, err
+		}
+		values[tfe.CategoryEnv] = parseDotenv(src)
+	}
+
+	return values, nil
+}
+
+// lookup return value of sensitive variable
+func (values SensitiveValues) lookup(v *tfe.Variable) (string, bool) {
+	value, ok := values[variableCategory(v)][v.Key]
+
+	return value, ok
+}
+
+func Copy(c *cli.Context) error {
+	ctx := context.Background()
+	log.Debug().Msg("copy command")
+
+	copyOpt := NewCopyOption(c)
+	if err := copyOpt.filter.Validate(); err != nil {
+		return err
+	}
+	fromOrg, fromWs, err := parseWorkspaceSpec(copyOpt.from)
+	if err != nil {
+		return fmt.Errorf("--from: %w", err)
+	}
+	toOrg, toWs, err := parseWorkspaceSpec(copyOpt.to)
+	if err != nil {
+		return fmt.Errorf("--to: %w", err)
+	}
+	values, err := NewSensitiveValues(copyOpt.varFile, copyOpt.envFile)
+	if err != nil {
+		return err
+	}
+
+	tfeClient, err := NewTfeClient(c)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build tfe client")
+		return err
+	}
+	from, err := tfeClient.Workspaces.Read(ctx, fromOrg, fromWs)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to access workspace %s", copyOpt.from)
+		return err
+	}
+	to, err := tfeClient.Workspaces.Read(ctx, toOrg, toWs)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to access workspace %s", copyOpt.to)
+		return err
+	}
+
+	return copyVariables(ctx, from.ID, to.ID, tfeClient.Variables, copyOpt, values)
+}
+
+// copyVariables create variables of source workspace in destination workspace
+func copyVariables(ctx context.Context, fromWorkspaceId string, toWorkspaceId string, tfeVariables tfe.Variables, copyOpt *CopyOption, values SensitiveValues) error {
+	srcVars, err := tfeVariables.List(ctx, fromWorkspaceId, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list variables of source workspace")
+		return err
+	}
+	destVars, err := tfeVariables.List(ctx, toWorkspaceId, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list variables of destination workspace")
+		return err
+	}
+
+	plan, err := planCopy(copyOpt.filter.Filter(srcVars.Items), destVars.Items, copyOpt.conflict, values)
+	if err != nil {
+		return err
+	}
+	plan.write(copyOpt.out, copyOpt.reveal)
+	if len(plan.variables) == 0 || copyOpt.dryRun {
+		return nil
+	}
+
+	if !copyOpt.autoApprove {
+		if err := requireTerminal(copyOpt.in); err != nil {
+			return err
+		}
+		fmt.Fprint(copyOpt.out, "\nAre you sure you want to copy variables in Terraform Cloud? [y/n]: ")
+		res, err := confirm(copyOpt.in)
+		if err != nil {
+			return err
+		}
+		if !res {
+			return nil
+		}
+	}
+
+	return applyPushVariables(ctx, toWorkspaceId, tfeVariables, plan.variables, copyOpt.out)
+}
+
+// copyPlan is operations to copy variables and keys not copied
+type copyPlan struct {
+	variables   []*PushVariable
+	skipped     []string // keys not changed, with reason as "key: reason"
+	placeholder []string // keys of sensitive variables created without value
+	kept        []string // keys of sensitive variables updated without value, which keep current values
+}
+
+// planCopy return operations to create source variables in destination
+// variables are identified by key and category, and existing ones are handled by conflict policy
+func planCopy(srcVars []*tfe.Variable, destVars []*tfe.Variable, conflict string, values SensitiveValues) (*copyPlan, error) {
+	plan := &copyPlan{
+		variables:   []*PushVariable{},
+		skipped:     []string{},
+		placeholder: []string{},
+		kept:        []string{},
+	}
+	conflicts := []string{}
+
+	for _, src := range srcVars {
+		dest := findVariable(destVars, src)
+		if dest != nil && conflict == CONFLICT_FAIL {
+			conflicts = append(conflicts, src.Key)
+			continue
+		}
+		if dest != nil && conflict != CONFLICT_OVERWRITE {
+			plan.skipped = append(plan.skipped, src.Key+": already exists")
+			continue
+		}
+
+		value := src.Value
+		found := true
+		if src.Sensitive {
+			value, found = values.lookup(src)
+		}
+
+		if dest == nil {
+			if !found {
+				value = copyPlaceholder(src)
+				plan.placeholder = append(plan.placeholder, src.Key)
+			}
+			plan.variables = append(plan.variables, &PushVariable{
+				operation: PUSH_OPERATION_CREATE,
+				createOption: tfe.VariableCreateOptions{
+					Key:         tfe.String(src.Key),
+					Value:       tfe.String(value),
+					Description: tfe.String(src.Description),
+					Category:    tfe.Category(variableCategory(src)),
+					HCL:         tfe.Bool(src.HCL),
+					Sensitive:   tfe.Bool(src.Sensitive),
+				},
+			})
+			continue
+		}
+
+		updateOption := tfe.VariableUpdateOptions{
+			Key:         tfe.String(src.Key),
+			Value:       tfe.String(value),
+			Description: tfe.String(src.Description),
+			Category:    tfe.Category(variableCategory(src)),
+			HCL:         tfe.Bool(src.HCL),
+			Sensitive:   tfe.Bool(src.Sensitive),
+		}
+		if !found {
+			// keep value in destination rather than overwriting it with placeholder
+			updateOption.Value = nil
+			plan.kept = append(plan.kept, src.Key)
+		}
+		plan.variables = append(plan.variables, &PushVariable{
+			operation:    PUSH_OPERATION_UPDATE,
+			id:           dest.ID,
+			previous:     dest,
+			updateOption: updateOption,
+		})
+	}
+
+	if len(conflicts) != 0 {
+		return nil, fmt.Errorf("variables already exist in destination workspace: %s", strings.Join(conflicts, ", "))
+	}
+
+	return plan, nil
+}
+
+// copyPlaceholder return value of sensitive variable created without its value
+func copyPlaceholder(v *tfe.Variable) string {
+	if v.HCL {
+		return "null"
+	}
+
+	return ""
+}
+
+// write print planned operations, skipped keys and keys which need values
+// values which look secret are masked unless reveal
+func (plan *copyPlan) write(out io.Writer, reveal bool) {
+	if len(plan.variables) == 0 && len(plan.skipped) == 0 {
+		fmt.Fprintln(out, "No changes.")
+		return
+	}
+
+	for _, variable := range plan.variables {
+		fmt.Fprintln(out, describePushVariable(variable, reveal))
+	}
+	for _, skipped := range plan.skipped {
+		fmt.Fprintf(out, "skip %s\n", skipped)
+	}
+	fmt.Fprintln(out, summarizePushVariables(plan.variables, len(plan.skipped)))
+
+	if len(plan.placeholder) != 0 {
+		fmt.Fprintln(out, "\nSensitive variables are created with placeholder values, set their values afterwards:")
+		for _, key := range plan.placeholder {
+			fmt.Fprintf(out, "  %s\n", key)
+		}
+	}
+	if len(plan.kept) != 0 {
+		fmt.Fprintln(out, "\nSensitive variables keep their current values since their values are not available:")
+		for _, key := range plan.kept {
+			fmt.Fprintf(out, "  %s\n", key)
+		}
+	}
+}
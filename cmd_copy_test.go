@@ -0,0 +1,375 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-tfe/mocks"
+	"github.com/urfave/cli/v2"
+)
+
+func TestCopyVariables(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVariables := mocks.NewMockVariables(ctrl)
+
+	srcVars := []*tfe.Variable{
+		{ID: "v-src-environment", Key: "environment", Value: "staging", Description: "env name", Category: tfe.CategoryTerraform},
+		{ID: "v-src-zones", Key: "zones", Value: `["a", "b"]`, Category: tfe.CategoryTerraform, HCL: true},
+		{ID: "v-src-db_password", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+		{ID: "v-src-AWS_REGION", Key: "AWS_REGION", Value: "ap-northeast-1", Category: tfe.CategoryEnv},
+	}
+
+	cases := []struct {
+		name      string
+		copyOpt   *CopyOption
+		values    SensitiveValues
+		destVars  []*tfe.Variable
+		setClient func(*mocks.MockVariables, string)
+		input     string
+		expect    string
+		wantErr   bool
+		expectErr string
+	}{
+		{
+			name:     "create all variables with attributes and placeholder for sensitive",
+			copyOpt:  &CopyOption{conflict: CONFLICT_SKIP, autoApprove: true},
+			destVars: []*tfe.Variable{},
+			setClient: func(mc *mocks.MockVariables, dest string) {
+				mc.EXPECT().Create(gomock.Any(), dest, tfe.VariableCreateOptions{
+					Key:         tfe.String("environment"),
+					Value:       tfe.String("staging"),
+					Description: tfe.String("env name"),
+					Category:    tfe.Category(tfe.CategoryTerraform),
+					HCL:         tfe.Bool(false),
+					Sensitive:   tfe.Bool(false),
+				}).Return(&tfe.Variable{}, nil)
+				mc.EXPECT().Create(gomock.Any(), dest, tfe.VariableCreateOptions{
+					Key:         tfe.String("zones"),
+					Value:       tfe.String(`["a", "b"]`),
+					Description: tfe.String(""),
+					Category:    tfe.Category(tfe.CategoryTerraform),
+					HCL:         tfe.Bool(true),
+					Sensitive:   tfe.Bool(false),
+				}).Return(&tfe.Variable{}, nil)
+				mc.EXPECT().Create(gomock.Any(), dest, tfe.VariableCreateOptions{
+					Key:         tfe.String("db_password"),
+					Value:       tfe.String(""),
+					Description: tfe.String(""),
+					Category:    tfe.Category(tfe.CategoryTerraform),
+					HCL:         tfe.Bool(false),
+					Sensitive:   tfe.Bool(true),
+				}).Return(&tfe.Variable{}, nil)
+				mc.EXPECT().Create(gomock.Any(), dest, tfe.VariableCreateOptions{
+					Key:         tfe.String("AWS_REGION"),
+					Value:       tfe.String("ap-northeast-1"),
+					Description: tfe.String(""),
+					Category:    tfe.Category(tfe.CategoryEnv),
+					HCL:         tfe.Bool(false),
+					Sensitive:   tfe.Bool(false),
+				}).Return(&tfe.Variable{}, nil)
+			},
+			expect: `create environment = "staging"
+create zones = "[\"a\", \"b\"]"
+create db_password = "(sensitive)"
+create AWS_REGION = "ap-northeast-1"
+Plan: 4 to create, 0 to update, 0 to delete, 0 skipped.
+
+Sensitive variables are created with placeholder values, set their values afterwards:
+  db_password
+`,
+		},
+		{
+			name:     "fill sensitive value from local file",
+			copyOpt:  &CopyOption{conflict: CONFLICT_SKIP, autoApprove: true, filter: &VariableFilter{keys: []string{"db_password"}}},
+			values:   SensitiveValues{tfe.CategoryTerraform: {"db_password": "supersecret"}},
+			destVars: []*tfe.Variable{},
+			setClient: func(mc *mocks.MockVariables, dest string) {
+				mc.EXPECT().Create(gomock.Any(), dest, tfe.VariableCreateOptions{
+					Key:         tfe.String("db_password"),
+					Value:       tfe.String("supersecret"),
+					Description: tfe.String(""),
+					Category:    tfe.Category(tfe.CategoryTerraform),
+					HCL:         tfe.Bool(false),
+					Sensitive:   tfe.Bool(true),
+				}).Return(&tfe.Variable{}, nil)
+			},
+			expect: "create db_password = \"(sensitive)\"\nPlan: 1 to create, 0 to update, 0 to delete, 0 skipped.\n",
+		},
+		{
+			name:    "print no changes if no variable selected",
+			copyOpt: &CopyOption{conflict: CONFLICT_SKIP, autoApprove: true, filter: &VariableFilter{category: "env", includes: []string{"env*"}}},
+			destVars: []*tfe.Variable{
+				{ID: "v-dest-AWS_REGION", Key: "AWS_REGION", Value: "us-east-1", Category: tfe.CategoryEnv},
+			},
+			setClient: func(mc *mocks.MockVariables, dest string) {},
+			expect:    "No changes.\n",
+		},
+		{
+			name:    "skip variable existing with same key and category",
+			copyOpt: &CopyOption{conflict: CONFLICT_SKIP, autoApprove: true, filter: &VariableFilter{keys: []string{"environment", "AWS_REGION"}}},
+			destVars: []*tfe.Variable{
+				{ID: "v-dest-environment", Key: "environment", Value: "production", Category: tfe.CategoryTerraform},
+				{ID: "v-dest-AWS_REGION", Key: "AWS_REGION", Value: "us-east-1", Category: tfe.CategoryTerraform},
+			},
+			setClient: func(mc *mocks.MockVariables, dest string) {
+				mc.EXPECT().Create(gomock.Any(), dest, gomock.Any()).Return(&tfe.Variable{}, nil)
+			},
+			expect: "create AWS_REGION = \"ap-northeast-1\"\nskip environment: already exists\nPlan: 1 to create, 0 to update, 0 to delete, 1 skipped.\n",
+		},
+		{
+			name:    "overwrite existing variables",
+			copyOpt: &CopyOption{conflict: CONFLICT_OVERWRITE, autoApprove: true, filter: &VariableFilter{keys: []string{"environment"}}},
+			destVars: []*tfe.Variable{
+				{ID: "v-dest-environment", Key: "environment", Value: "production", Category: tfe.CategoryTerraform},
+			},
+			setClient: func(mc *mocks.MockVariables, dest string) {
+				mc.EXPECT().Update(gomock.Any(), dest, "v-dest-environment", tfe.VariableUpdateOptions{
+					Key:         tfe.String("environment"),
+					Value:       tfe.String("staging"),
+					Description: tfe.String("env name"),
+					Category:    tfe.Category(tfe.CategoryTerraform),
+					HCL:         tfe.Bool(false),
+					Sensitive:   tfe.Bool(false),
+				}).Return(&tfe.Variable{}, nil)
+			},
+			expect: "update environment: \"production\" -> \"staging\"\n~ environment: description \"\" -> \"env name\"\nPlan: 0 to create, 1 to update, 0 to delete, 0 skipped.\n",
+		},
+		{
+			name:    "keep value of existing sensitive variable if local value not found",
+			copyOpt: &CopyOption{conflict: CONFLICT_OVERWRITE, autoApprove: true, filter: &VariableFilter{keys: []string{"db_password"}}},
+			destVars: []*tfe.Variable{
+				{ID: "v-dest-db_password", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+			},
+			setClient: func(mc *mocks.MockVariables, dest string) {
+				mc.EXPECT().Update(gomock.Any(), dest, "v-dest-db_password", tfe.VariableUpdateOptions{
+					Key:         tfe.String("db_password"),
+					Description: tfe.String(""),
+					Category:    tfe.Category(tfe.CategoryTerraform),
+					HCL:         tfe.Bool(false),
+					Sensitive:   tfe.Bool(true),
+				}).Return(&tfe.Variable{}, nil)
+			},
+			expect: "update db_password: \"(sensitive)\" -> \"(sensitive)\"\nPlan: 0 to create, 1 to update, 0 to delete, 0 skipped.\n\nSensitive variables keep their current values since their values are not available:\n  db_password\n",
+		},
+		{
+			name:    "fail if variables exist in destination",
+			copyOpt: &CopyOption{conflict: CONFLICT_FAIL, autoApprove: true},
+			destVars: []*tfe.Variable{
+				{ID: "v-dest-environment", Key: "environment", Value: "production", Category: tfe.CategoryTerraform},
+				{ID: "v-dest-zones", Key: "zones", Value: `["c"]`, Category: tfe.CategoryTerraform, HCL: true},
+			},
+			setClient: func(mc *mocks.MockVariables, dest string) {},
+			wantErr:   true,
+			expectErr: "variables already exist in destination workspace: environment, zones",
+		},
+		{
+			name:      "print plan without copying in dry-run mode",
+			copyOpt:   &CopyOption{conflict: CONFLICT_SKIP, dryRun: true, filter: &VariableFilter{keys: []string{"environment"}}},
+			destVars:  []*tfe.Variable{},
+			setClient: func(mc *mocks.MockVariables, dest string) {},
+			expect:    "create environment = \"staging\"\nPlan: 1 to create, 0 to update, 0 to delete, 0 skipped.\n",
+		},
+		{
+			name:      "do nothing if approve declined",
+			copyOpt:   &CopyOption{conflict: CONFLICT_SKIP, filter: &VariableFilter{keys: []string{"environment"}}},
+			destVars:  []*tfe.Variable{},
+			setClient: func(mc *mocks.MockVariables, dest string) {},
+			input:     "n\n",
+			expect:    "create environment = \"staging\"\nPlan: 1 to create, 0 to update, 0 to delete, 0 skipped.\n\nAre you sure you want to copy variables in Terraform Cloud? [y/n]: ",
+		},
+		{
+			name:     "continue copying and report failed keys",
+			copyOpt:  &CopyOption{conflict: CONFLICT_SKIP, autoApprove: true, filter: &VariableFilter{keys: []string{"environment", "zones"}}},
+			destVars: []*tfe.Variable{},
+			setClient: func(mc *mocks.MockVariables, dest string) {
+				gomock.InOrder(
+					mc.EXPECT().Create(gomock.Any(), dest, gomock.Any()).Return(nil, errors.New("permission denied")),
+					mc.EXPECT().Create(gomock.Any(), dest, gomock.Any()).Return(&tfe.Variable{}, nil),
+				)
+			},
+			wantErr:   true,
+			expectErr: "failed to create environment: permission denied",
+		},
+	}
+
+	for idx, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			src := "ws-copy-src-" + string(rune('a'+idx))
+			dest := "ws-copy-dest-" + string(rune('a'+idx))
+			mockVariables.EXPECT().List(gomock.Any(), src, nil).Return(&tfe.VariableList{Items: srcVars}, nil)
+			mockVariables.EXPECT().List(gomock.Any(), dest, nil).Return(&tfe.VariableList{Items: tt.destVars}, nil)
+			tt.setClient(mockVariables, dest)
+			tt.copyOpt.in = strings.NewReader(tt.input)
+			outBuf := new(bytes.Buffer)
+			tt.copyOpt.out = outBuf
+
+			err := copyVariables(ctx, src, dest, mockVariables, tt.copyOpt, tt.values)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expect '%s' error, got no error", tt.expectErr)
+				} else if !strings.Contains(err.Error(), tt.expectErr) {
+					t.Errorf("expect '%s' error, got '%s'", tt.expectErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+			if outBuf.String() != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, outBuf.String())
+			}
+		})
+	}
+}
+
+func TestCopyPlanWrite(t *testing.T) {
+	plan := &copyPlan{
+		variables: []*PushVariable{
+			{
+				operation: PUSH_OPERATION_CREATE,
+				createOption: tfe.VariableCreateOptions{
+					Key:       tfe.String("api_token"),
+					Value:     tfe.String("plain-token"),
+					Category:  tfe.Category(tfe.CategoryTerraform),
+					HCL:       tfe.Bool(false),
+					Sensitive: tfe.Bool(false),
+				},
+			},
+			{
+				operation: PUSH_OPERATION_UPDATE,
+				id:        "v-db_password",
+				previous:  &tfe.Variable{ID: "v-db_password", Key: "db_password", Value: "old-password", Category: tfe.CategoryTerraform},
+				updateOption: tfe.VariableUpdateOptions{
+					Key:       tfe.String("db_password"),
+					Value:     tfe.String("new-password"),
+					Category:  tfe.Category(tfe.CategoryTerraform),
+					HCL:       tfe.Bool(false),
+					Sensitive: tfe.Bool(false),
+				},
+			},
+			{
+				operation: PUSH_OPERATION_CREATE,
+				createOption: tfe.VariableCreateOptions{
+					Key:       tfe.String("environment"),
+					Value:     tfe.String("staging"),
+					Category:  tfe.Category(tfe.CategoryTerraform),
+					HCL:       tfe.Bool(false),
+					Sensitive: tfe.Bool(false),
+				},
+			},
+		},
+		skipped: []string{},
+	}
+
+	cases := []struct {
+		name   string
+		reveal bool
+		expect string
+	}{
+		{
+			name:   "mask values which look secret",
+			expect: "create api_token = \"(redacted)\"\nupdate db_password: \"(redacted)\" -> \"(redacted, changed)\"\ncreate environment = \"staging\"\nPlan: 2 to create, 1 to update, 0 to delete, 0 skipped.\n",
+		},
+		{
+			name:   "show values which look secret with reveal",
+			reveal: true,
+			expect: "create api_token = \"plain-token\"\nupdate db_password: \"old-password\" -> \"new-password\"\ncreate environment = \"staging\"\nPlan: 2 to create, 1 to update, 0 to delete, 0 skipped.\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			outBuf := new(bytes.Buffer)
+			plan.write(outBuf, tt.reveal)
+
+			if outBuf.String() != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, outBuf.String())
+			}
+		})
+	}
+}
+
+func TestNewSensitiveValues(t *testing.T) {
+	dir := t.TempDir()
+	envFile := dir + "/.env"
+	if err := os.WriteFile(envFile, []byte("AWS_SECRET_ACCESS_KEY=\"secret\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := NewSensitiveValues("testdata/sensitive.tfvars", envFile)
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	expect := SensitiveValues{
+		tfe.CategoryTerraform: {"environment": "test", "db_password": "supersecret"},
+		tfe.CategoryEnv:       {"AWS_SECRET_ACCESS_KEY": "secret"},
+	}
+	if !reflect.DeepEqual(expect, values) {
+		t.Errorf("expect '%v', got '%v'", expect, values)
+	}
+
+	_, err = NewSensitiveValues("testdata/not-exist.tfvars", "")
+	if err == nil {
+		t.Errorf("expect error for missing var-file, got no error")
+	}
+}
+
+func TestNewCopyOption(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		expect *CopyOption
+	}{
+		{
+			name: "default value",
+			args: []string{"--from", "org/src", "--to", "org/dest"},
+			expect: &CopyOption{
+				from:     "org/src",
+				to:       "org/dest",
+				conflict: CONFLICT_SKIP,
+				in:       os.Stdin,
+				out:      os.Stdout,
+			},
+		},
+		{
+			name: "specify all options",
+			args: []string{"--from", "org/src", "--to", "other/dest", "--variable", "environment", "--include", "db_*", "--conflict", "overwrite", "--dry-run", "--var-file", "secrets.tfvars", "--env-file", ".env", "--auto-approve"},
+			expect: &CopyOption{
+				from:        "org/src",
+				to:          "other/dest",
+				filter:      &VariableFilter{keys: []string{"environment"}, includes: []string{"db_*"}},
+				conflict:    CONFLICT_OVERWRITE,
+				dryRun:      true,
+				varFile:     "secrets.tfvars",
+				envFile:     ".env",
+				autoApprove: true,
+				in:          os.Stdin,
+				out:         os.Stdout,
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			app := cli.NewApp()
+			set := flagSet(copyFlags())
+			set.Parse(tt.args)
+			ctx := cli.NewContext(app, set, nil)
+
+			sut := NewCopyOption(ctx)
+
+			if !reflect.DeepEqual(tt.expect, sut) {
+				t.Errorf("expect '%+v', got '%+v'", tt.expect, sut)
+			}
+		})
+	}
+}
@@ -2,24 +2,39 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	tfe "github.com/hashicorp/go-tfe"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rs/zerolog/log"
-	"github.com/sergi/go-diff/diffmatchpatch"
 	"github.com/urfave/cli/v2"
 	"github.com/zclconf/go-cty/cty"
 )
 
+// errDrift is returned if local files differ from Terraform Cloud in drift check mode
+var errDrift = errors.New("drift detected")
+
 type DiffOption struct {
 	varFile            string
 	includeEnv         bool
 	includeVariableSet bool
+	filter             *VariableFilter
+	detailedExitcode   bool
+	format             string
+	syncDescriptions   bool
+	from               string
+	to                 string
+	typeChanges        bool
+	color              string
+	context            int
+	limitContext       bool
+	reveal             bool
 }
 
 func NewDiffOption(c *cli.Context) *DiffOption {
@@ -28,6 +43,17 @@ func NewDiffOption(c *cli.Context) *DiffOption {
 	opt.varFile = c.String("var-file")
 	opt.includeEnv = c.Bool("include-env")
 	opt.includeVariableSet = c.Bool("include-variable-set")
+	opt.filter = NewVariableFilter(c, c.StringSlice("variable"))
+	opt.detailedExitcode = c.Bool("detailed-exitcode")
+	opt.format = c.String("format")
+	opt.syncDescriptions = c.Bool("sync-descriptions")
+	opt.from = c.String("from")
+	opt.to = c.String("to")
+	opt.typeChanges = c.Bool("type-changes")
+	opt.color = c.String("color")
+	opt.context = c.Int("context")
+	opt.limitContext = c.IsSet("context")
+	opt.reveal = c.Bool("reveal")
 
 	return opt
 }
@@ -37,12 +63,28 @@ func Diff(c *cli.Context) error {
 	log.Debug().Msg("show command")
 
 	diffOpt := NewDiffOption(c)
+	if err := diffOpt.filter.Validate(); err != nil {
+		return err
+	}
 
 	tfeClient, err := NewTfeClient(c)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to build tfe client")
 		return err
 	}
+
+	if diffOpt.from != "" || diffOpt.to != "" {
+		from, to, err := diffOpt.sources()
+		if err != nil {
+			return err
+		}
+		err = diffSources(ctx, from, to, tfeClient.Workspaces, tfeClient.Variables, tfeClient.VariableSets, tfeClient.VariableSetVariables, diffOpt, os.Stdout)
+		if errors.Is(err, errDrift) {
+			return cli.Exit("", 2)
+		}
+		return err
+	}
+
 	organization, workspaceName = updateTerraformCloudWorkspace(organization, workspaceName, ".")
 	w, err := tfeClient.Workspaces.Read(ctx, organization, workspaceName)
 	if err != nil {
@@ -50,78 +92,240 @@ func Diff(c *cli.Context) error {
 		return err
 	}
 
-	return diff(ctx, w.ID, tfeClient.Variables, tfeClient.VariableSets, tfeClient.VariableSetVariables, diffOpt, os.Stdout)
+	err = diff(ctx, w.ID, tfeClient.Variables, tfeClient.VariableSets, tfeClient.VariableSetVariables, diffOpt, os.Stdout)
+	if errors.Is(err, errDrift) {
+		return cli.Exit("", 2)
+	}
+
+	return err
+}
+
+// sources parse --from and --to options
+// current workspace is used if --from is omitted, and var-file if --to is omitted
+func (opt *DiffOption) sources() (*DiffSource, *DiffSource, error) {
+	var from, to *DiffSource
+	var err error
+
+	if opt.from != "" {
+		from, err = NewDiffSource(opt.from)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		org, ws := updateTerraformCloudWorkspace(organization, workspaceName, ".")
+		from = &DiffSource{organization: org, workspace: ws}
+	}
+
+	if opt.to != "" {
+		to, err = NewDiffSource(opt.to)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		to = &DiffSource{varFile: opt.varFile}
+	}
+
+	return from, to, nil
 }
 
 func diff(ctx context.Context, workspaceId string, tfeVariables tfe.Variables, tfeVariableSets tfe.VariableSets, tfeVariableSetVariables tfe.VariableSetVariables, diffOpt *DiffOption, w io.Writer) error {
-	varsSrc, err := tfeVariables.List(ctx, workspaceId, nil)
+	from, err := newRemoteDiffSide(ctx, workspaceId, tfeVariables, tfeVariableSets, tfeVariableSetVariables, diffOpt)
+	if err != nil {
+		return err
+	}
+	to, err := newLocalDiffSide(diffOpt.varFile, diffOpt)
+	if err != nil {
+		return err
+	}
+
+	return writeDiff(w, from, to, diffOpt)
+}
+
+// diffSources print difference between two workspaces or var-files
+func diffSources(ctx context.Context, fromSource *DiffSource, toSource *DiffSource, tfeWorkspaces tfe.Workspaces, tfeVariables tfe.Variables, tfeVariableSets tfe.VariableSets, tfeVariableSetVariables tfe.VariableSetVariables, diffOpt *DiffOption, w io.Writer) error {
+	from, err := loadDiffSide(ctx, fromSource, tfeWorkspaces, tfeVariables, tfeVariableSets, tfeVariableSetVariables, diffOpt)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to list variables")
 		return err
 	}
-	if diffOpt.includeVariableSet {
-		variableSetVariables, err := listVariableSetVariables(ctx, workspaceId, tfeVariableSets, tfeVariableSetVariables)
+	to, err := loadDiffSide(ctx, toSource, tfeWorkspaces, tfeVariables, tfeVariableSets, tfeVariableSetVariables, diffOpt)
+	if err != nil {
+		return err
+	}
+
+	return writeDiff(w, from, to, diffOpt)
+}
+
+// writeDiff print difference from one side to the other in the format of diff options
+// values which look secret are masked unless reveal is enabled
+// return errDrift if difference found and detailed exitcode is enabled
+func writeDiff(w io.Writer, from *diffSide, to *diffSide, diffOpt *DiffOption) error {
+	if diffOpt.format == "unified" {
+		return writeUnifiedDiff(w, from, to, diffOpt)
+	}
+
+	comparison := compareDiffSides(from, to, diffOpt)
+	from, to = comparison.from, comparison.to
+	sensitive := comparison.sensitive
+	fromVars, toVars := comparison.fromVars, comparison.toVars
+	comparator := comparison.comparator
+	entries := comparison.entries
+	renderOpt := &DiffRenderOption{
+		color:   useColor(diffOpt.color, w),
+		context: -1,
+	}
+	if diffOpt.limitContext {
+		renderOpt.context = diffOpt.context
+	}
+
+	var includeDiff bool
+	switch diffOpt.format {
+	case "json":
+		includeDiff = hasDiffEntryChange(entries)
+		err := writeDiffJSON(w, entries)
 		if err != nil {
-			log.Error().Err(err).Msg("failed to list VariableSetVariables")
 			return err
 		}
-		varsSrc.Items = append(varsSrc.Items, variableSetVariables...)
+	case "summary":
+		includeDiff = hasDiffEntryChange(entries)
+		writeDiffSummary(w, entries, from.name, to.name)
+	default:
+		toTfvars := to.render(sensitive)
+		fromText, err := destBasedText(NewTfvarsVariable(fromVars), toTfvars, unchangedKeys(fromVars, toVars, comparator))
+		if err != nil {
+			return err
+		}
+		lines := diffLines(fromText, toTfvars.BuildHCLFileString())
+		includeDiff = hasChange(lines)
+		if includeDiff {
+			fmt.Fprint(w, renderDiff(lines, renderOpt))
+		}
+		if writeMetadataChanges(w, entries, renderOpt.color) {
+			includeDiff = true
+		}
 	}
-	if !diffOpt.includeEnv {
-		varsSrc.Items = FilterEnv(varsSrc.Items)
+
+	if includeDiff && diffOpt.detailedExitcode {
+		return errDrift
+	}
+
+	return nil
+}
+
+// writeUnifiedDiff print patch to apply to the local file, from the local file to the other side
+// patch is built from raw contents so that it keeps comments and layout of the local file,
+// and refused if any value is masked since applying it would write masks into the file
+func writeUnifiedDiff(w io.Writer, from *diffSide, to *diffSide, diffOpt *DiffOption) error {
+	if keys := maskedKeys(from, to, diffOpt.reveal); len(keys) != 0 {
+		return fmt.Errorf("cannot build patch since values of %s are masked: use --reveal for secret-looking values or exclude sensitive variables", strings.Join(keys, ", "))
 	}
-	vfSrc := NewTfvarsVariable(varsSrc.Items)
 
-	vfDest, err := NewTfvarsFile(diffOpt.varFile)
+	comparison := compareDiffSides(from, to, diffOpt)
+	toTfvars := to.tfvars
+	if toTfvars == nil {
+		toTfvars = to.render(nil)
+	}
+	fromText, err := destBasedText(NewTfvarsVariable(from.vars), toTfvars, unchangedKeys(comparison.fromVars, comparison.toVars, comparison.comparator))
 	if err != nil {
 		return err
 	}
 
-	includeDiff, diffString := destBasedDiff(vfSrc, vfDest)
-	if includeDiff {
-		fmt.Fprint(w, diffString)
+	renderOpt := &DiffRenderOption{
+		color:   useColor(diffOpt.color, w),
+		context: -1,
+	}
+	if diffOpt.limitContext {
+		renderOpt.context = diffOpt.context
+	}
+	includeDiff, diffString := unifiedDiff(to.name, to.name, string(toTfvars.vardata), fromText, renderOpt)
+	fmt.Fprint(w, diffString)
+
+	if includeDiff && diffOpt.detailedExitcode {
+		return errDrift
 	}
 
 	return nil
 }
 
-func fileDiff(srcText, destText string) (bool, string) {
-	includeDiff := false
+// maskedKeys return sorted keys whose values are masked in diff
+// values of sensitive variables are always masked, and secret-looking values unless reveal is enabled
+func maskedKeys(from *diffSide, to *diffSide, reveal bool) []string {
+	sensitive := sensitiveKeys(from.vars, to.vars)
+	masked := map[string]bool{}
+	for _, vars := range [][]*tfe.Variable{from.vars, to.vars} {
+		for _, v := range vars {
+			if sensitive[v.Key] || (!reveal && looksSecret(v)) {
+				masked[v.Key] = true
+			}
+		}
+	}
 
-	dmp := diffmatchpatch.New()
-	a, b, c := dmp.DiffLinesToChars(srcText, destText)
-	diffs := dmp.DiffMain(a, b, false)
-	diffs = dmp.DiffCharsToLines(diffs, c)
-	var buf strings.Builder
+	keys := []string{}
+	for key := range masked {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-	for _, diff := range diffs {
-		prefix := "  "
-		if diff.Type == diffmatchpatch.DiffDelete {
-			includeDiff = true
-			prefix = "- "
-		} else if diff.Type == diffmatchpatch.DiffInsert {
-			includeDiff = true
-			prefix = "+ "
-		}
+	return keys
+}
 
-		lines := strings.Split(diff.Text, "\n")
-		for i, line := range lines {
-			if i == len(lines)-1 {
-				continue
-			}
-			buf.WriteString(prefix + line + "\n")
-		}
+// diffComparison is variables of both sides masked for output and their differences
+type diffComparison struct {
+	from       *diffSide
+	to         *diffSide
+	sensitive  map[string]bool
+	fromVars   []*tfe.Variable
+	toVars     []*tfe.Variable
+	comparator *diffComparator
+	entries    []*DiffEntry
+}
+
+// compareDiffSides mask sensitive values and secrets unless reveal is enabled, and compare both sides
+func compareDiffSides(from *diffSide, to *diffSide, diffOpt *DiffOption) *diffComparison {
+	sensitive := sensitiveKeys(from.vars, to.vars)
+	if !diffOpt.reveal {
+		from, to = redactSecretSides(from, to, sensitive, diffOpt.typeChanges)
+	}
+	comparison := &diffComparison{
+		from:      from,
+		to:        to,
+		sensitive: sensitive,
+		fromVars:  from.maskedVars(sensitive),
+		toVars:    to.maskedVars(sensitive),
+		comparator: &diffComparator{
+			oldValues:   from.values(sensitive),
+			newValues:   to.values(sensitive),
+			metadata:    metadataComparator(from, to, diffOpt.syncDescriptions),
+			typeChanges: diffOpt.typeChanges,
+		},
 	}
+	comparison.entries = buildDiffEntries(comparison.fromVars, comparison.toVars, comparison.comparator)
 
-	return includeDiff, buf.String()
+	return comparison
+}
+
+func fileDiff(srcText, destText string) (bool, string) {
+	lines := diffLines(srcText, destText)
+
+	return hasChange(lines), renderDiff(lines, &DiffRenderOption{context: -1})
 }
 
 // destBasedDiff creates a diff based on destination file format(includeing comments and variable order)
-func destBasedDiff(srcVariable *Tfvars, destText *Tfvars) (bool, string) {
+// attributes in keep are written as in destination since their values are equivalent
+func destBasedDiff(srcVariable *Tfvars, destText *Tfvars, keep map[string]bool) (bool, string) {
+	srcText, err := destBasedText(srcVariable, destText, keep)
+	if err != nil {
+		return false, ""
+	}
+
+	return fileDiff(srcText, destText.BuildHCLFileString())
+}
+
+// destBasedText render srcVariable in destination file format
+func destBasedText(srcVariable *Tfvars, destText *Tfvars, keep map[string]bool) (string, error) {
 	w, diag := hclwrite.ParseConfig(destText.vardata, srcVariable.filename, hcl.InitialPos)
 	if diag.HasErrors() {
 		log.Error().Msg("failed to parse src file")
-		return false, ""
+		return "", errors.New(diag.Error())
 	}
 
 	// remove attributes defined in destText but not in srcVariable
@@ -138,6 +342,14 @@ destVariableLoop:
 
 	// add or update attributes defined in srcVariable
 	for _, v := range srcVariable.vars {
+		if keep[v.Key] && w.Body().GetAttribute(v.Key) != nil {
+			continue
+		}
+		if v.Sensitive {
+			// value of sensitive variable cannot be retrieved from Terraform Cloud
+			w.Body().SetAttributeValue(v.Key, cty.StringVal(sensitiveMask))
+			continue
+		}
 		ctyValue := CtyValue(v.Value)
 		if IsPrimitive(ctyValue) {
 			w.Body().SetAttributeValue(v.Key, cty.StringVal(v.Value))
@@ -146,5 +358,5 @@ destVariableLoop:
 		}
 	}
 
-	return fileDiff(string(w.Bytes()), destText.BuildHCLFileString())
+	return string(w.Bytes()), nil
 }
@@ -4,7 +4,11 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
@@ -131,6 +135,341 @@ func TestCmdDiff(t *testing.T) {
 			},
 			expect: "",
 		},
+		{
+			name:        "mask local value of remote sensitive variable",
+			workspaceId: "w-test-mask-sensitive-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/sensitive.tfvars"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-mask-sensitive-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "development",
+							},
+							{
+								Key:       "db_password",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "- environment = \"development\"\n+ environment = \"test\"\n  db_password = \"(sensitive)\"\n",
+		},
+		{
+			name:        "mask secret-looking value",
+			workspaceId: "w-test-mask-secret-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/sensitive.tfvars"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-mask-secret-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "development",
+							},
+							{
+								Key:   "db_password",
+								Value: "supersecret",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "- environment = \"development\"\n+ environment = \"test\"\n  db_password = \"(redacted)\"\n",
+		},
+		{
+			name:        "mask changed secret-looking value",
+			workspaceId: "w-test-mask-changed-secret-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/sensitive.tfvars"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-mask-changed-secret-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "development",
+							},
+							{
+								Key:   "db_password",
+								Value: "oldsecret",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "- environment = \"development\"\n- db_password = \"(redacted)\"\n+ environment = \"test\"\n+ db_password = \"(redacted, changed)\"\n",
+		},
+		{
+			name:        "reveal secret-looking value",
+			workspaceId: "w-test-reveal-secret-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/sensitive.tfvars", reveal: true},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-reveal-secret-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "development",
+							},
+							{
+								Key:   "db_password",
+								Value: "oldsecret",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "- environment = \"development\"\n- db_password = \"oldsecret\"\n+ environment = \"test\"\n+ db_password = \"supersecret\"\n",
+		},
+		{
+			name:        "show diff only for selected variables",
+			workspaceId: "w-test-filter-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/mixedtypes.tfvars", filter: &VariableFilter{keys: []string{"environment", "port"}}},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-filter-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "production",
+							},
+							{
+								Key:   "port",
+								Value: "3000",
+								HCL:   true,
+							},
+							{
+								Key:   "terraform",
+								Value: "false",
+								HCL:   true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "- environment = \"production\"\n+ environment = \"test\"\n  port        = \"3000\"\n",
+		},
+		{
+			name:        "show diff in json format",
+			workspaceId: "w-test-json-format-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/terraform.tfvars", format: "json"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-json-format-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "production",
+							},
+							{
+								Key:       "db_password",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: `[
+  {
+    "key": "db_password",
+    "change": "removed",
+    "old": "(sensitive)",
+    "sensitive": true
+  },
+  {
+    "key": "environment",
+    "change": "changed",
+    "old": "production",
+    "new": "development",
+    "sensitive": false
+  }
+]
+`,
+		},
+		{
+			name:        "show diff in summary format",
+			workspaceId: "w-test-summary-format-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/terraform.tfvars", format: "summary"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-summary-format-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "development",
+							},
+							{
+								Key:   "region",
+								Value: "ap-northeast-1",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "Only in w-test-summary-format-workspace (1):\n  region\nIn both (1):\n  environment\n",
+		},
+		{
+			name:        "show metadata changes",
+			workspaceId: "w-test-metadata-changes-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/withcomment.tfvars", includeEnv: true, syncDescriptions: true},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-metadata-changes-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:         "environment",
+								Value:       "test",
+								Description: "env",
+							},
+							{
+								Key:      "port",
+								Value:    "3000",
+								Category: tfe.CategoryEnv,
+							},
+							{
+								Key:   "terraform",
+								Value: "true",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "~ port: description \"\" -> \"port\"\n",
+		},
+		{
+			name:        "show no diff with semantically equal values",
+			workspaceId: "w-test-semantic-equal-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/semantic.tfvars"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-semantic-equal-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "port",
+								Value: "3000",
+							},
+							{
+								Key:   "tags",
+								Value: `{ b = "2", a = "1" }`,
+								HCL:   true,
+							},
+							{
+								Key:   "zones",
+								Value: `["a","b"]`,
+								HCL:   true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "",
+		},
+		{
+			name:        "show type only changes",
+			workspaceId: "w-test-type-changes-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/semantic.tfvars", typeChanges: true},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-type-changes-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "port",
+								Value: "3000",
+							},
+							{
+								Key:   "tags",
+								Value: `{ b = "2", a = "1" }`,
+								HCL:   true,
+							},
+							{
+								Key:   "zones",
+								Value: `["a","b"]`,
+								HCL:   true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "- port = \"3000\"\n+ port = 3000\n  tags = {\n    a = \"1\"\n    b = \"2\"\n  }\n  zones = [\"a\", \"b\"]\n",
+		},
+		{
+			name:        "show diff in unified format",
+			workspaceId: "w-test-unified-format-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/withcomment.tfvars", format: "unified", limitContext: true, context: 1},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-unified-format-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "test",
+							},
+							{
+								Key:   "port",
+								Value: "8080",
+							},
+							{
+								Key:   "terraform",
+								Value: "true",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "--- testdata/withcomment.tfvars\n+++ testdata/withcomment.tfvars\n@@ -3,3 +3,3 @@\n # port\n-port      = \"3000\"\n+port      = \"8080\"\n terraform = \"true\"\n",
+		},
+		{
+			name:        "return drift error with detailed exitcode",
+			workspaceId: "w-test-detailed-exitcode-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/terraform.tfvars", detailedExitcode: true},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-detailed-exitcode-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "production",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			wantErr:   true,
+			expectErr: "drift detected",
+		},
+		{
+			name:        "return no error without diff with detailed exitcode",
+			workspaceId: "w-test-detailed-exitcode-no-diff-workspace",
+			diffOpt:     &DiffOption{varFile: "testdata/terraform.tfvars", detailedExitcode: true},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-detailed-exitcode-no-diff-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "development",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "",
+		},
 		{
 			name:        "show diff with different key",
 			workspaceId: "w-test-single-variable-different-key-workspace",
@@ -423,6 +762,9 @@ func TestCmdDiff(t *testing.T) {
 			if bufString := replaceNBSPWithSpace(buf.String()); bufString != tt.expect {
 				t.Errorf("expect: '%s', got: '%s'", tt.expect, bufString)
 			}
+			if !tt.diffOpt.reveal && strings.Contains(buf.String(), "supersecret") {
+				t.Errorf("expect sensitive value not to be written, got '%s'", buf.String())
+			}
 		})
 	}
 }
@@ -437,6 +779,8 @@ func TestNewDiffOption(t *testing.T) {
 			name: "default value",
 			args: []string{},
 			expect: &DiffOption{
+				format:  "text",
+				color:   "auto",
 				varFile: "terraform.tfvars",
 			},
 		},
@@ -444,6 +788,8 @@ func TestNewDiffOption(t *testing.T) {
 			name: "default value",
 			args: []string{"--var-file", "testdata/terraform.tfvars"},
 			expect: &DiffOption{
+				format:  "text",
+				color:   "auto",
 				varFile: "testdata/terraform.tfvars",
 			},
 		},
@@ -451,14 +797,49 @@ func TestNewDiffOption(t *testing.T) {
 			name: "enable include env option",
 			args: []string{"--include-env"},
 			expect: &DiffOption{
+				format:     "text",
+				color:      "auto",
 				varFile:    "terraform.tfvars",
 				includeEnv: true,
 			},
 		},
+		{
+			name: "enable detailed exitcode option",
+			args: []string{"--detailed-exitcode"},
+			expect: &DiffOption{
+				format:           "text",
+				color:            "auto",
+				varFile:          "terraform.tfvars",
+				detailedExitcode: true,
+			},
+		},
+		{
+			name: "enable sync descriptions option",
+			args: []string{"--sync-descriptions"},
+			expect: &DiffOption{
+				format:           "text",
+				color:            "auto",
+				varFile:          "terraform.tfvars",
+				syncDescriptions: true,
+			},
+		},
+		{
+			name: "enable context option",
+			args: []string{"--context", "1", "--color", "never"},
+			expect: &DiffOption{
+				format:       "text",
+				color:        "never",
+				context:      1,
+				limitContext: true,
+				varFile:      "terraform.tfvars",
+			},
+		},
 		{
 			name: "enable include variable set option",
 			args: []string{"--include-variable-set"},
 			expect: &DiffOption{
+				format:             "text",
+				color:              "auto",
 				varFile:            "terraform.tfvars",
 				includeVariableSet: true,
 			},
@@ -489,3 +870,297 @@ func replaceNBSPWithSpace(s string) string {
 		return r
 	}, s)
 }
+
+func TestDiffSources(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockWorkspaces := mocks.NewMockWorkspaces(ctrl)
+	mockVariables := mocks.NewMockVariables(ctrl)
+	mockVariableSets := mocks.NewMockVariableSets(ctrl)
+	mockVariableSetVariables := mocks.NewMockVariableSetVariables(ctrl)
+
+	mockWorkspaces.EXPECT().
+		Read(context.TODO(), "org", "staging").
+		Return(&tfe.Workspace{ID: "w-staging"}, nil).
+		AnyTimes()
+	mockWorkspaces.EXPECT().
+		Read(context.TODO(), "org", "production").
+		Return(&tfe.Workspace{ID: "w-production"}, nil).
+		AnyTimes()
+	mockVariables.EXPECT().
+		List(context.TODO(), "w-staging", nil).
+		Return(&tfe.VariableList{
+			Items: []*tfe.Variable{
+				{Key: "environment", Value: "staging", Category: tfe.CategoryTerraform},
+				{Key: "db_password", Sensitive: true, Category: tfe.CategoryTerraform},
+				{Key: "AWS_REGION", Value: "ap-northeast-1", Category: tfe.CategoryEnv},
+			},
+		}, nil).
+		AnyTimes()
+	mockVariables.EXPECT().
+		List(context.TODO(), "w-production", nil).
+		Return(&tfe.VariableList{
+			Items: []*tfe.Variable{
+				{Key: "environment", Value: "production", Category: tfe.CategoryTerraform},
+				{Key: "db_password", Value: "supersecret", Category: tfe.CategoryTerraform},
+				{Key: "AWS_REGION", Value: "us-east-1", Category: tfe.CategoryEnv},
+			},
+		}, nil).
+		AnyTimes()
+
+	cases := []struct {
+		name    string
+		from    *DiffSource
+		to      *DiffSource
+		diffOpt *DiffOption
+		expect  string
+	}{
+		{
+			name:    "compare workspaces",
+			from:    &DiffSource{organization: "org", workspace: "staging"},
+			to:      &DiffSource{organization: "org", workspace: "production"},
+			diffOpt: &DiffOption{},
+			expect:  "- environment = \"staging\"\n+ environment = \"production\"\n  db_password = \"(sensitive)\"\n~ db_password: sensitive true -> false\n",
+		},
+		{
+			name:    "compare workspaces including env variables",
+			from:    &DiffSource{organization: "org", workspace: "staging"},
+			to:      &DiffSource{organization: "org", workspace: "production"},
+			diffOpt: &DiffOption{includeEnv: true, format: "summary"},
+			expect:  "In both (3):\n  AWS_REGION\n  db_password\n  environment\n",
+		},
+		{
+			name:    "compare local file with workspace",
+			from:    &DiffSource{varFile: "testdata/terraform.tfvars"},
+			to:      &DiffSource{organization: "org", workspace: "staging"},
+			diffOpt: &DiffOption{},
+			expect:  "- environment = \"development\"\n+ environment = \"staging\"\n+ db_password = \"(sensitive)\"\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := diffSources(context.TODO(), tt.from, tt.to, mockWorkspaces, mockVariables, mockVariableSets, mockVariableSetVariables, tt.diffOpt, &buf)
+
+			if err != nil {
+				t.Errorf("expect no error, got error: '%v'", err)
+			}
+			if bufString := replaceNBSPWithSpace(buf.String()); bufString != tt.expect {
+				t.Errorf("expect: '%s', got: '%s'", tt.expect, bufString)
+			}
+			if strings.Contains(buf.String(), "supersecret") {
+				t.Errorf("expect sensitive value not to be written, got '%s'", buf.String())
+			}
+		})
+	}
+}
+
+func TestNewDiffSource(t *testing.T) {
+	cases := []struct {
+		name    string
+		spec    string
+		expect  *DiffSource
+		wantErr bool
+	}{
+		{
+			name:   "workspace",
+			spec:   "org/staging",
+			expect: &DiffSource{organization: "org", workspace: "staging"},
+		},
+		{
+			name:   "existing var-file",
+			spec:   "testdata/terraform.tfvars",
+			expect: &DiffSource{varFile: "testdata/terraform.tfvars"},
+		},
+		{
+			name:   "var-file not exist",
+			spec:   "production.tfvars",
+			expect: &DiffSource{varFile: "production.tfvars"},
+		},
+		{
+			name:    "invalid workspace",
+			spec:    "org/staging/extra",
+			wantErr: true,
+		},
+		{
+			name:    "workspace without organization",
+			spec:    "staging",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			sut, err := NewDiffSource(tt.spec)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expect error, got no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expect no error, got error: '%v'", err)
+			}
+			if !reflect.DeepEqual(tt.expect, sut) {
+				t.Errorf("expect '%v', got '%v'", tt.expect, sut)
+			}
+		})
+	}
+}
+
+func TestWriteUnifiedDiffPatch(t *testing.T) {
+	if _, err := exec.LookPath("patch"); err != nil {
+		t.Skip("patch is not installed")
+	}
+
+	local := `# env
+environment = "test"
+# token for api
+api_token = "ghp4f7Kx9Qm2Lz8Rt6Vb1Nc3"
+port      = "3000"
+`
+	remote := []*tfe.Variable{
+		{Key: "environment", Value: "production"},
+		{Key: "api_token", Value: "ghp9Zx8Yw7Vu6Ts5Rq4Po3Nm2"},
+		{Key: "port", Value: "3000"},
+	}
+
+	cases := []struct {
+		name      string
+		remote    []*tfe.Variable
+		diffOpt   *DiffOption
+		expect    string
+		wantErr   bool
+		expectErr string
+	}{
+		{
+			name:      "refuse patch with secret-looking values masked",
+			remote:    remote,
+			diffOpt:   &DiffOption{format: "unified"},
+			wantErr:   true,
+			expectErr: "values of api_token are masked",
+		},
+		{
+			name:      "refuse patch with sensitive values",
+			remote:    append([]*tfe.Variable{{Key: "db_password", Sensitive: true}}, remote...),
+			diffOpt:   &DiffOption{format: "unified", reveal: true},
+			wantErr:   true,
+			expectErr: "values of db_password are masked",
+		},
+		{
+			name:    "apply patch to local file keeping comments",
+			remote:  remote,
+			diffOpt: &DiffOption{format: "unified", reveal: true},
+			expect: `# env
+environment = "production"
+# token for api
+api_token = "ghp9Zx8Yw7Vu6Ts5Rq4Po3Nm2"
+port      = "3000"
+`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			varFile := filepath.Join(t.TempDir(), "terraform.tfvars")
+			if err := os.WriteFile(varFile, []byte(local), 0644); err != nil {
+				t.Fatal(err)
+			}
+			to, err := newLocalDiffSide(varFile, tt.diffOpt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			from := &diffSide{name: "ws-test", vars: tt.remote}
+			outBuf := new(bytes.Buffer)
+
+			err = writeDiff(outBuf, from, to, tt.diffOpt)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expect '%s' error, got no error", tt.expectErr)
+				} else if !strings.Contains(err.Error(), tt.expectErr) {
+					t.Errorf("expect '%s' error, got '%s'", tt.expectErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got error: %v", err)
+			}
+
+			cmd := exec.Command("patch", "-s", varFile)
+			cmd.Stdin = outBuf
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("failed to apply patch: %v: %s", err, out)
+			}
+			actual, err := os.ReadFile(varFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, actual)
+			}
+			for _, mask := range []string{sensitiveMask, redactedMask, redactedChangedMask} {
+				if strings.Contains(string(actual), mask) {
+					t.Errorf("expect no mask '%s' in patched file, got '%s'", mask, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffAndPushReportSameMetadataChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVariables := mocks.NewMockVariables(ctrl)
+	remoteVars := []*tfe.Variable{
+		{ID: "v-environment", Key: "environment", Value: "test", Description: "old description", Category: tfe.CategoryTerraform},
+		{ID: "v-zones", Key: "zones", Value: `["a"]`, Category: tfe.CategoryTerraform},
+		{ID: "v-region", Key: "region", Value: "us-east-1", Description: "AWS region", Category: tfe.CategoryTerraform},
+		{ID: "v-db_password", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+	}
+	mockVariables.EXPECT().
+		List(context.TODO(), "w-test-same-metadata-changes", nil).
+		Return(&tfe.VariableList{Items: remoteVars}, nil).
+		AnyTimes()
+
+	varFile := filepath.Join(t.TempDir(), "terraform.tfvars")
+	src := "# Environment name\nenvironment = \"test\"\nzones = [\"a\", \"b\"]\nregion = \"us-east-1\"\ndb_password = \"supersecret\"\n"
+	if err := os.WriteFile(varFile, []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	vf, err := NewTfvarsFile(varFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metadataLines := func(out string) []string {
+		lines := []string{}
+		for _, line := range strings.Split(out, "\n") {
+			if strings.HasPrefix(line, "~ ") {
+				lines = append(lines, line)
+			}
+		}
+		sort.Strings(lines)
+		return lines
+	}
+
+	diffBuf := new(bytes.Buffer)
+	diffOpt := &DiffOption{varFile: varFile, syncDescriptions: true, color: COLOR_NEVER}
+	if err := diff(context.TODO(), "w-test-same-metadata-changes", mockVariables, nil, nil, diffOpt, diffBuf); err != nil {
+		t.Fatalf("expect no error in diff, got error: %v", err)
+	}
+	pushBuf := new(bytes.Buffer)
+	pushOpt := &PushOption{syncDescriptions: true, in: strings.NewReader("n\n"), out: pushBuf}
+	if err := push(context.TODO(), "w-test-same-metadata-changes", mockVariables, pushOpt, &tfe.VariableList{Items: vf.vars}); err != nil {
+		t.Fatalf("expect no error in push, got error: %v", err)
+	}
+
+	expect := []string{`~ environment: description "old description" -> "Environment name"`}
+	if actual := metadataLines(diffBuf.String()); !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect diff to report '%v', got '%v'", expect, actual)
+	}
+	if actual := metadataLines(pushBuf.String()); !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect push to report '%v', got '%v'", expect, actual)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+type ExportOption struct {
+	organization       string
+	outDir             string
+	includeVariableSet bool
+	out                io.Writer
+}
+
+func NewExportOption(c *cli.Context) *ExportOption {
+	var opt = &ExportOption{}
+
+	opt.organization = c.String("org")
+	if opt.organization == "" {
+		opt.organization = organization
+	}
+	opt.outDir = c.String("out")
+	opt.includeVariableSet = c.Bool("include-variable-set")
+
+	opt.out = os.Stdout
+
+	return opt
+}
+
+func Export(c *cli.Context) error {
+	ctx := context.Background()
+	log.Debug().Msg("export command")
+
+	exportOpt := NewExportOption(c)
+	if exportOpt.organization == "" {
+		return errors.New("organization is not specified: use --org")
+	}
+
+	tfeClient, err := NewTfeClient(c)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build tfe client")
+		return err
+	}
+
+	manifest := &BackupManifest{
+		Version:      backupVersion,
+		Organization: exportOpt.organization,
+		ExportedAt:   time.Now().UTC(),
+	}
+	manifest.Workspaces, err = exportWorkspaces(ctx, tfeClient.Workspaces, tfeClient.Variables, exportOpt)
+	if err != nil {
+		return err
+	}
+	if exportOpt.includeVariableSet {
+		manifest.VariableSets, err = exportVariableSets(ctx, tfeClient.VariableSets, tfeClient.VariableSetVariables, exportOpt)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := writeBackupFile(exportOpt.outDir, backupManifestFile, manifest); err != nil {
+		log.Error().Err(err).Msg("failed to write manifest")
+		return err
+	}
+	fmt.Fprintf(exportOpt.out, "Exported %d workspaces and %d variable sets to %s\n", len(manifest.Workspaces), len(manifest.VariableSets), exportOpt.outDir)
+
+	return nil
+}
+
+// exportWorkspaces write variables of every workspace in organization into files
+func exportWorkspaces(ctx context.Context, tfeWorkspaces tfe.Workspaces, tfeVariables tfe.Variables, exportOpt *ExportOption) ([]*BackupEntry, error) {
+	entries := []*BackupEntry{}
+
+	for page := 1; page != 0; {
+		workspaces, err := tfeWorkspaces.List(ctx, exportOpt.organization, &tfe.WorkspaceListOptions{
+			ListOptions: tfe.ListOptions{PageNumber: page, PageSize: listPageSize},
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("failed to list workspaces in organization %s", exportOpt.organization)
+			return nil, err
+		}
+
+		for _, workspace := range workspaces.Items {
+			variables, err := tfeVariables.List(ctx, workspace.ID, nil)
+			if err != nil {
+				log.Error().Err(err).Msgf("failed to list variables of workspace %s", workspace.Name)
+				return nil, err
+			}
+
+			file := filepath.Join("workspaces", workspace.Name+".json")
+			if err := writeBackupFile(exportOpt.outDir, file, newVariableOutputs(variables.Items, nil)); err != nil {
+				log.Error().Err(err).Msgf("failed to write variables of workspace %s", workspace.Name)
+				return nil, err
+			}
+			entries = append(entries, newBackupEntry(workspace.ID, workspace.Name, file, variables.Items))
+			fmt.Fprintf(exportOpt.out, "export workspace %s: %d variables\n", workspace.Name, len(variables.Items))
+		}
+
+		page = nextPage(workspaces.Pagination)
+	}
+
+	return entries, nil
+}
+
+// exportVariableSets write variables of every variable set in organization into files
+// files are named by ID since names of variable set may contain any characters
+func exportVariableSets(ctx context.Context, tfeVariableSets tfe.VariableSets, tfeVariableSetVariables tfe.VariableSetVariables, exportOpt *ExportOption) ([]*BackupEntry, error) {
+	entries := []*BackupEntry{}
+
+	for page := 1; page != 0; {
+		variableSets, err := tfeVariableSets.List(ctx, exportOpt.organization, &tfe.VariableSetListOptions{
+			ListOptions: tfe.ListOptions{PageNumber: page, PageSize: listPageSize},
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("failed to list variable sets in organization %s", exportOpt.organization)
+			return nil, err
+		}
+
+		for _, variableSet := range variableSets.Items {
+			variableList, err := tfeVariableSetVariables.List(ctx, variableSet.ID, nil)
+			if err != nil {
+				log.Error().Err(err).Msgf("failed to list VariableSetVariables ID: %s", variableSet.ID)
+				return nil, err
+			}
+			variables := []*tfe.Variable{}
+			for _, v := range variableList.Items {
+				variables = append(variables, convertVariableSetVariable(v))
+			}
+
+			file := filepath.Join("variable-sets", variableSet.ID+".json")
+			if err := writeBackupFile(exportOpt.outDir, file, newVariableOutputs(variables, nil)); err != nil {
+				log.Error().Err(err).Msgf("failed to write variables of variable set %s", variableSet.Name)
+				return nil, err
+			}
+			entries = append(entries, newBackupEntry(variableSet.ID, variableSet.Name, file, variables))
+			fmt.Fprintf(exportOpt.out, "export variable set %s: %d variables\n", variableSet.Name, len(variables))
+		}
+
+		page = nextPage(variableSets.Pagination)
+	}
+
+	return entries, nil
+}
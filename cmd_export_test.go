@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-tfe/mocks"
+	"github.com/urfave/cli/v2"
+)
+
+func TestExportWorkspaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockWorkspaces := mocks.NewMockWorkspaces(ctrl)
+	mockVariables := mocks.NewMockVariables(ctrl)
+
+	dir := t.TempDir()
+	outBuf := new(bytes.Buffer)
+	exportOpt := &ExportOption{organization: "org", outDir: dir, out: outBuf}
+
+	gomock.InOrder(
+		mockWorkspaces.EXPECT().List(gomock.Any(), "org", &tfe.WorkspaceListOptions{
+			ListOptions: tfe.ListOptions{PageNumber: 1, PageSize: listPageSize},
+		}).Return(&tfe.WorkspaceList{
+			Pagination: &tfe.Pagination{CurrentPage: 1, NextPage: 2, TotalPages: 2},
+			Items:      []*tfe.Workspace{{ID: "ws-production", Name: "production"}},
+		}, nil),
+		mockWorkspaces.EXPECT().List(gomock.Any(), "org", &tfe.WorkspaceListOptions{
+			ListOptions: tfe.ListOptions{PageNumber: 2, PageSize: listPageSize},
+		}).Return(&tfe.WorkspaceList{
+			Pagination: &tfe.Pagination{CurrentPage: 2, NextPage: 0, TotalPages: 2},
+			Items:      []*tfe.Workspace{{ID: "ws-staging", Name: "staging"}},
+		}, nil),
+	)
+	mockVariables.EXPECT().List(gomock.Any(), "ws-production", nil).Return(&tfe.VariableList{
+		Items: []*tfe.Variable{
+			{ID: "v-environment", Key: "environment", Value: "production", Category: tfe.CategoryTerraform},
+			{ID: "v-db_password", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+		},
+	}, nil)
+	mockVariables.EXPECT().List(gomock.Any(), "ws-staging", nil).Return(&tfe.VariableList{
+		Items: []*tfe.Variable{},
+	}, nil)
+
+	entries, err := exportWorkspaces(context.TODO(), mockWorkspaces, mockVariables, exportOpt)
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+
+	expectEntries := []*BackupEntry{
+		{ID: "ws-production", Name: "production", File: filepath.Join("workspaces", "production.json"), Variables: 2, Sensitive: []string{"db_password"}},
+		{ID: "ws-staging", Name: "staging", File: filepath.Join("workspaces", "staging.json"), Variables: 0},
+	}
+	if !reflect.DeepEqual(expectEntries, entries) {
+		t.Errorf("expect '%+v', got '%+v'", expectEntries, entries)
+	}
+	expectOut := "export workspace production: 2 variables\nexport workspace staging: 0 variables\n"
+	if outBuf.String() != expectOut {
+		t.Errorf("expect '%s', got '%s'", expectOut, outBuf.String())
+	}
+
+	variables, err := readBackupVariables(dir, entries[0])
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	expectVars := []*tfe.Variable{
+		{ID: "v-environment", Key: "environment", Value: "production", Category: tfe.CategoryTerraform},
+		{ID: "v-db_password", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+	}
+	if !reflect.DeepEqual(expectVars, variables) {
+		t.Errorf("expect '%+v', got '%+v'", expectVars, variables)
+	}
+	info, err := os.Stat(filepath.Join(dir, entries[0].File))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != backupFileMode {
+		t.Errorf("expect file mode %v, got %v", backupFileMode, info.Mode().Perm())
+	}
+}
+
+func TestExportVariableSets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVariableSets := mocks.NewMockVariableSets(ctrl)
+	mockVariableSetVariables := mocks.NewMockVariableSetVariables(ctrl)
+
+	dir := t.TempDir()
+	exportOpt := &ExportOption{organization: "org", outDir: dir, out: new(bytes.Buffer)}
+
+	mockVariableSets.EXPECT().List(gomock.Any(), "org", &tfe.VariableSetListOptions{
+		ListOptions: tfe.ListOptions{PageNumber: 1, PageSize: listPageSize},
+	}).Return(&tfe.VariableSetList{
+		Items: []*tfe.VariableSet{{ID: "varset-aws", Name: "aws credentials"}},
+	}, nil)
+	mockVariableSetVariables.EXPECT().List(gomock.Any(), "varset-aws", nil).Return(&tfe.VariableSetVariableList{
+		Items: []*tfe.VariableSetVariable{
+			{ID: "var-region", Key: "AWS_REGION", Value: "ap-northeast-1", Category: tfe.CategoryEnv},
+		},
+	}, nil)
+
+	entries, err := exportVariableSets(context.TODO(), mockVariableSets, mockVariableSetVariables, exportOpt)
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+
+	expectEntries := []*BackupEntry{
+		{ID: "varset-aws", Name: "aws credentials", File: filepath.Join("variable-sets", "varset-aws.json"), Variables: 1},
+	}
+	if !reflect.DeepEqual(expectEntries, entries) {
+		t.Errorf("expect '%+v', got '%+v'", expectEntries, entries)
+	}
+	variables, err := readBackupVariables(dir, entries[0])
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	expectVars := []*tfe.Variable{
+		{ID: "var-region", Key: "AWS_REGION", Value: "ap-northeast-1", Category: tfe.CategoryEnv},
+	}
+	if !reflect.DeepEqual(expectVars, variables) {
+		t.Errorf("expect '%+v', got '%+v'", expectVars, variables)
+	}
+}
+
+func TestReadBackupManifest(t *testing.T) {
+	dir := t.TempDir()
+	manifest := &BackupManifest{
+		Version:      backupVersion,
+		Organization: "org",
+		Workspaces:   []*BackupEntry{{ID: "ws-production", Name: "production", File: "workspaces/production.json", Variables: 1}},
+	}
+	if err := writeBackupFile(dir, backupManifestFile, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readBackupManifest(dir)
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	if !reflect.DeepEqual(manifest, got) {
+		t.Errorf("expect '%+v', got '%+v'", manifest, got)
+	}
+
+	manifest.Version = backupVersion + 1
+	if err := writeBackupFile(dir, backupManifestFile, manifest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readBackupManifest(dir); err == nil {
+		t.Errorf("expect error for unsupported version, got no error")
+	}
+}
+
+func TestNewExportOption(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		expect *ExportOption
+	}{
+		{
+			name: "default value",
+			args: []string{"--org", "org"},
+			expect: &ExportOption{
+				organization: "org",
+				outDir:       "tfcvars-backup",
+				out:          os.Stdout,
+			},
+		},
+		{
+			name: "specify all options",
+			args: []string{"--org", "org", "--out", "backup", "--include-variable-set"},
+			expect: &ExportOption{
+				organization:       "org",
+				outDir:             "backup",
+				includeVariableSet: true,
+				out:                os.Stdout,
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			app := cli.NewApp()
+			set := flagSet(exportFlags())
+			set.Parse(tt.args)
+			ctx := cli.NewContext(app, set, nil)
+
+			sut := NewExportOption(ctx)
+
+			if !reflect.DeepEqual(tt.expect, sut) {
+				t.Errorf("expect '%+v', got '%+v'", tt.expect, sut)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// guardPathspec is files checked by guard command if no file is specified
+const guardPathspec = "*.tfvars"
+
+type GuardOption struct {
+	files              []string
+	includeVariableSet bool
+	format             string
+	out                io.Writer
+}
+
+func NewGuardOption(c *cli.Context) *GuardOption {
+	var opt = &GuardOption{}
+
+	opt.files = c.Args().Slice()
+	opt.includeVariableSet = c.Bool("include-variable-set")
+	opt.format = c.String("format")
+
+	opt.out = os.Stdout
+
+	return opt
+}
+
+func Guard(c *cli.Context) error {
+	ctx := context.Background()
+	log.Debug().Msg("guard command")
+
+	guardOpt := NewGuardOption(c)
+
+	pathspecs := guardOpt.files
+	if len(pathspecs) == 0 {
+		pathspecs = []string{guardPathspec}
+	}
+	files, err := gitTrackedFiles(".", pathspecs)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		log.Debug().Msg("no tfvars file tracked by git")
+		return writeLintDiagnostics(guardOpt.out, []*LintDiagnostic{}, guardOpt.format)
+	}
+
+	tfeClient, err := NewTfeClient(c)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build tfe client")
+		return err
+	}
+	organization, workspaceName = updateTerraformCloudWorkspace(organization, workspaceName, ".")
+	w, err := tfeClient.Workspaces.Read(ctx, organization, workspaceName)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to access workspace %s/%s", organization, workspaceName)
+		return err
+	}
+	sensitive, err := listSensitiveKeys(ctx, w.ID, tfeClient.Variables)
+	if err != nil {
+		return err
+	}
+	if guardOpt.includeVariableSet {
+		variableSetSensitive, err := listVariableSetSensitiveKeys(ctx, w.ID, tfeClient.VariableSets, tfeClient.VariableSetVariables)
+		if err != nil {
+			return err
+		}
+		for key := range variableSetSensitive {
+			sensitive[key] = true
+		}
+	}
+
+	diagnostics, err := guardFiles(files, sensitive)
+	if err != nil {
+		return err
+	}
+	if err := writeLintDiagnostics(guardOpt.out, diagnostics, guardOpt.format); err != nil {
+		return err
+	}
+	if len(diagnostics) != 0 {
+		return cli.Exit("", 1)
+	}
+
+	return nil
+}
+
+// guardFiles return diagnostics of variables sensitive in Terraform Cloud with plaintext values in files
+func guardFiles(files []string, sensitive map[string]bool) ([]*LintDiagnostic, error) {
+	diagnostics := []*LintDiagnostic{}
+
+	for _, filename := range files {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		if isEncrypted(src) {
+			log.Debug().Msgf("skip encrypted file: %s", filename)
+			continue
+		}
+		for _, diagnostic := range lintTfvars(filename, src, nil, sensitive) {
+			if diagnostic.Rule == LINT_RULE_REMOTE_SENSITIVE {
+				diagnostics = append(diagnostics, diagnostic)
+			}
+		}
+	}
+
+	return diagnostics, nil
+}
+
+// gitTrackedFiles return files matching pathspecs which are tracked or staged in git repository of dir
+// returned paths are relative to dir
+func gitTrackedFiles(dir string, pathspecs []string) ([]string, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd := exec.Command("git", append([]string{"ls-files", "-z", "--"}, pathspecs...)...)
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		log.Error().Err(err).Msgf("failed to list files tracked by git")
+		return nil, fmt.Errorf("failed to list files tracked by git: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	files := []string{}
+	for _, file := range strings.Split(stdout.String(), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+func TestGuardFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"terraform.tfvars":   "environment = \"production\"\ndb_password = \"supersecret\"\n",
+		"placeholder.tfvars": "environment = \"production\"\ndb_password = \"\"\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name      string
+		files     []string
+		sensitive map[string]bool
+		expect    []string
+	}{
+		{
+			name:      "report plaintext value of sensitive variable",
+			files:     []string{filepath.Join(dir, "terraform.tfvars")},
+			sensitive: map[string]bool{"db_password": true},
+			expect:    []string{filepath.Join(dir, "terraform.tfvars") + ":2,1-28"},
+		},
+		{
+			name:      "ignore placeholder value",
+			files:     []string{filepath.Join(dir, "placeholder.tfvars")},
+			sensitive: map[string]bool{"db_password": true},
+			expect:    []string{},
+		},
+		{
+			name:      "ignore other rules",
+			files:     []string{filepath.Join(dir, "terraform.tfvars")},
+			sensitive: map[string]bool{},
+			expect:    []string{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics, err := guardFiles(tt.files, tt.sensitive)
+
+			if err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+			actual := []string{}
+			for _, diagnostic := range diagnostics {
+				if diagnostic.Rule != LINT_RULE_REMOTE_SENSITIVE {
+					t.Errorf("expect only %s rule, got %s", LINT_RULE_REMOTE_SENSITIVE, diagnostic.Rule)
+				}
+				actual = append(actual, diagnostic.Range.String())
+			}
+			if !reflect.DeepEqual(tt.expect, actual) {
+				t.Errorf("expect '%v', got '%v'", tt.expect, actual)
+			}
+		})
+	}
+}
+
+func TestGitTrackedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	for _, name := range []string{"terraform.tfvars", "envs/production.tfvars", "local.tfvars", "main.tf"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(""), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("add", "terraform.tfvars", "envs/production.tfvars", "main.tf")
+
+	cases := []struct {
+		name      string
+		pathspecs []string
+		expect    []string
+	}{
+		{
+			name:      "tracked tfvars files",
+			pathspecs: []string{guardPathspec},
+			expect:    []string{"envs/production.tfvars", "terraform.tfvars"},
+		},
+		{
+			name:      "skip untracked file",
+			pathspecs: []string{"terraform.tfvars", "local.tfvars"},
+			expect:    []string{"terraform.tfvars"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := gitTrackedFiles(dir, tt.pathspecs)
+
+			if err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+			if !reflect.DeepEqual(tt.expect, files) {
+				t.Errorf("expect '%v', got '%v'", tt.expect, files)
+			}
+		})
+	}
+}
+
+func TestNewGuardOption(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		expect *GuardOption
+	}{
+		{
+			name:   "default value",
+			args:   []string{},
+			expect: &GuardOption{files: []string{}, format: "text", out: os.Stdout},
+		},
+		{
+			name:   "specify all options",
+			args:   []string{"--include-variable-set", "--format", "json", "terraform.tfvars"},
+			expect: &GuardOption{files: []string{"terraform.tfvars"}, includeVariableSet: true, format: "json", out: os.Stdout},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			app := cli.NewApp()
+			set := flagSet(guardFlags())
+			set.Parse(tt.args)
+			ctx := cli.NewContext(app, set, nil)
+
+			sut := NewGuardOption(ctx)
+
+			if !reflect.DeepEqual(tt.expect, sut) {
+				t.Errorf("expect '%+v', got '%+v'", tt.expect, sut)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+type HistoryOption struct {
+	limit  int
+	format string
+	reveal bool
+	out    io.Writer
+}
+
+func NewHistoryOption(c *cli.Context) *HistoryOption {
+	var opt = &HistoryOption{}
+
+	opt.limit = c.Int("limit")
+	opt.format = c.String("format")
+	opt.reveal = c.Bool("reveal")
+
+	opt.out = os.Stdout
+
+	return opt
+}
+
+func History(c *cli.Context) error {
+	log.Debug().Msg("history command")
+
+	historyOpt := NewHistoryOption(c)
+	if changeJournal == nil {
+		return errors.New("change journal is not available")
+	}
+	entries, err := changeJournal.entries()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to read journal")
+		return err
+	}
+
+	return writeHistory(historyOpt.out, entries, historyOpt)
+}
+
+// writeHistory print latest entries first, up to limit entries if limit is positive
+func writeHistory(w io.Writer, entries []*JournalEntry, historyOpt *HistoryOption) error {
+	latest := []*JournalEntry{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if historyOpt.limit > 0 && len(latest) >= historyOpt.limit {
+			break
+		}
+		latest = append(latest, entries[i])
+	}
+	if !historyOpt.reveal {
+		latest = redactJournalEntries(latest)
+	}
+
+	if historyOpt.format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(latest)
+	}
+
+	if len(latest) == 0 {
+		fmt.Fprintln(w, "No changes recorded.")
+		return nil
+	}
+	for _, entry := range latest {
+		fmt.Fprintf(w, "#%d %s %s %s %s\n", entry.ID, entry.Time.Format("2006-01-02 15:04:05Z07:00"), entry.Command, entry.Target, entry.TargetID)
+		for _, op := range entry.Operations {
+			fmt.Fprintf(w, "  %s\n", op.describe())
+		}
+	}
+
+	return nil
+}
+
+// redactJournalEntries return copy of entries whose values looking secret are masked
+func redactJournalEntries(entries []*JournalEntry) []*JournalEntry {
+	redacted := []*JournalEntry{}
+	for _, entry := range entries {
+		e := *entry
+		e.Operations = []*JournalOperation{}
+		for _, operation := range entry.Operations {
+			op := *operation
+			op.Before = op.Before.redact(op.Key)
+			op.After = op.After.redact(op.Key)
+			e.Operations = append(e.Operations, &op)
+		}
+		redacted = append(redacted, &e)
+	}
+
+	return redacted
+}
+
+// redact return copy of recorded variable whose value is masked if it looks secret
+func (jv *JournalVariable) redact(key string) *JournalVariable {
+	if jv == nil || jv.Value == nil || !looksSecret(jv.variable(key)) {
+		return jv
+	}
+	v := *jv
+	v.Value = tfe.String(redactedMask)
+
+	return &v
+}
+
+// describe return one line description of recorded operation with previous value
+func (op *JournalOperation) describe() string {
+	description := fmt.Sprintf("%s %s", op.Operation, op.Key)
+	if op.Before != nil {
+		description += fmt.Sprintf(" (was %q)", op.Before.value())
+	}
+	if op.Before != nil && op.After != nil {
+		for _, change := range variableMetadataChanges(op.Before.variable(op.Key), op.After.variable(op.Key)) {
+			description += "\n  " + formatMetadataChange(op.Key, change)
+		}
+	}
+
+	return description
+}
+
+// value return recorded value, or mask if not recorded
+func (jv *JournalVariable) value() string {
+	if jv.Value == nil {
+		return redactValue("", true)
+	}
+
+	return *jv.Value
+}
+
+// variable return tfe variable with recorded attributes
+func (jv *JournalVariable) variable(key string) *tfe.Variable {
+	v := &tfe.Variable{
+		Key:         key,
+		Description: jv.Description,
+		Category:    jv.Category,
+		HCL:         jv.HCL,
+		Sensitive:   jv.Sensitive,
+	}
+	if jv.Value != nil {
+		v.Value = *jv.Value
+	}
+
+	return v
+}
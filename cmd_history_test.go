@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/urfave/cli/v2"
+)
+
+func TestWriteHistory(t *testing.T) {
+	entries := []*JournalEntry{
+		{
+			ID:       1,
+			Time:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Command:  "push",
+			Target:   JOURNAL_TARGET_WORKSPACE,
+			TargetID: "ws-test",
+			Operations: []*JournalOperation{
+				{Operation: PUSH_OPERATION_CREATE, Key: "zones", After: &JournalVariable{Category: tfe.CategoryTerraform, HCL: true}},
+				{
+					Operation: PUSH_OPERATION_UPDATE,
+					Key:       "environment",
+					Before:    &JournalVariable{Value: tfe.String("staging"), Category: tfe.CategoryTerraform},
+					After:     &JournalVariable{Description: "env name", Category: tfe.CategoryTerraform},
+				},
+			},
+		},
+		{
+			ID:       2,
+			Time:     time.Date(2024, 1, 3, 3, 4, 5, 0, time.UTC),
+			Command:  "rm",
+			Target:   JOURNAL_TARGET_VARIABLE_SET,
+			TargetID: "varset-test",
+			Operations: []*JournalOperation{
+				{Operation: PUSH_OPERATION_DELETE, Key: "db_password", Before: &JournalVariable{Category: tfe.CategoryTerraform, Sensitive: true}},
+			},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		entries    []*JournalEntry
+		historyOpt *HistoryOption
+		expect     string
+	}{
+		{
+			name:       "print latest entries first",
+			entries:    entries,
+			historyOpt: &HistoryOption{limit: 20, format: "text"},
+			expect: `#2 2024-01-03 03:04:05Z rm variable-set varset-test
+  delete db_password (was "(sensitive)")
+#1 2024-01-02 03:04:05Z push workspace ws-test
+  create zones
+  update environment (was "staging")
+  ~ environment: description "" -> "env name"
+`,
+		},
+		{
+			name:       "print up to limit",
+			entries:    entries,
+			historyOpt: &HistoryOption{limit: 1, format: "text"},
+			expect:     "#2 2024-01-03 03:04:05Z rm variable-set varset-test\n  delete db_password (was \"(sensitive)\")\n",
+		},
+		{
+			name: "mask values looking secret",
+			entries: []*JournalEntry{
+				{
+					ID:       3,
+					Time:     time.Date(2024, 1, 4, 3, 4, 5, 0, time.UTC),
+					Command:  "push",
+					Target:   JOURNAL_TARGET_WORKSPACE,
+					TargetID: "ws-test",
+					Operations: []*JournalOperation{
+						{
+							Operation: PUSH_OPERATION_UPDATE,
+							Key:       "api_token",
+							Before:    &JournalVariable{Value: tfe.String("old-token"), Category: tfe.CategoryTerraform},
+							After:     &JournalVariable{Value: tfe.String("new-token"), Category: tfe.CategoryTerraform},
+						},
+					},
+				},
+			},
+			historyOpt: &HistoryOption{format: "text"},
+			expect:     "#3 2024-01-04 03:04:05Z push workspace ws-test\n  update api_token (was \"(redacted)\")\n",
+		},
+		{
+			name: "reveal values looking secret",
+			entries: []*JournalEntry{
+				{
+					ID:       3,
+					Time:     time.Date(2024, 1, 4, 3, 4, 5, 0, time.UTC),
+					Command:  "push",
+					Target:   JOURNAL_TARGET_WORKSPACE,
+					TargetID: "ws-test",
+					Operations: []*JournalOperation{
+						{
+							Operation: PUSH_OPERATION_UPDATE,
+							Key:       "api_token",
+							Before:    &JournalVariable{Value: tfe.String("old-token"), Category: tfe.CategoryTerraform},
+							After:     &JournalVariable{Value: tfe.String("new-token"), Category: tfe.CategoryTerraform},
+						},
+					},
+				},
+			},
+			historyOpt: &HistoryOption{format: "text", reveal: true},
+			expect:     "#3 2024-01-04 03:04:05Z push workspace ws-test\n  update api_token (was \"old-token\")\n",
+		},
+		{
+			name:       "print message if no entry",
+			entries:    []*JournalEntry{},
+			historyOpt: &HistoryOption{format: "text"},
+			expect:     "No changes recorded.\n",
+		},
+		{
+			name:       "print json",
+			entries:    entries[1:],
+			historyOpt: &HistoryOption{format: "json"},
+			expect: `[
+  {
+    "id": 2,
+    "time": "2024-01-03T03:04:05Z",
+    "command": "rm",
+    "target": "variable-set",
+    "target_id": "varset-test",
+    "operations": [
+      {
+        "operation": "delete",
+        "key": "db_password",
+        "before": {
+          "description": "",
+          "category": "terraform",
+          "hcl": false,
+          "sensitive": true
+        }
+      }
+    ]
+  }
+]
+`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			outBuf := new(bytes.Buffer)
+
+			err := writeHistory(outBuf, tt.entries, tt.historyOpt)
+
+			if err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+			if outBuf.String() != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, outBuf.String())
+			}
+		})
+	}
+}
+
+func TestNewHistoryOption(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		expect *HistoryOption
+	}{
+		{
+			name:   "default value",
+			args:   []string{},
+			expect: &HistoryOption{limit: 20, format: "text", out: os.Stdout},
+		},
+		{
+			name:   "specify all options",
+			args:   []string{"--limit", "0", "--format", "json", "--reveal"},
+			expect: &HistoryOption{limit: 0, format: "json", reveal: true, out: os.Stdout},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			app := cli.NewApp()
+			set := flagSet(historyFlags())
+			set.Parse(tt.args)
+			ctx := cli.NewContext(app, set, nil)
+
+			sut := NewHistoryOption(ctx)
+
+			if !reflect.DeepEqual(tt.expect, sut) {
+				t.Errorf("expect '%+v', got '%+v'", tt.expect, sut)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// sarifSchema is JSON schema of SARIF output
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type LintOption struct {
+	files  []string
+	format string
+	remote bool
+	out    io.Writer
+}
+
+func NewLintOption(c *cli.Context) *LintOption {
+	var opt = &LintOption{}
+
+	opt.files = c.Args().Slice()
+	if len(opt.files) == 0 {
+		opt.files = []string{"terraform.tfvars"}
+	}
+	opt.format = c.String("format")
+	opt.remote = c.Bool("remote")
+
+	opt.out = os.Stdout
+
+	return opt
+}
+
+func Lint(c *cli.Context) error {
+	ctx := context.Background()
+	log.Debug().Msg("lint command")
+
+	lintOpt := NewLintOption(c)
+
+	var remoteSensitive map[string]bool
+	if lintOpt.remote {
+		tfeClient, err := NewTfeClient(c)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to build tfe client")
+			return err
+		}
+		organization, workspaceName = updateTerraformCloudWorkspace(organization, workspaceName, ".")
+		w, err := tfeClient.Workspaces.Read(ctx, organization, workspaceName)
+		if err != nil {
+			log.Error().Err(err).Msgf("failed to access workspace %s/%s", organization, workspaceName)
+			return err
+		}
+		remoteSensitive, err = listSensitiveKeys(ctx, w.ID, tfeClient.Variables)
+		if err != nil {
+			return err
+		}
+	}
+
+	diagnostics, err := lintFiles(lintOpt.files, remoteSensitive)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to lint files")
+		return err
+	}
+	if err := writeLintDiagnostics(lintOpt.out, diagnostics, lintOpt.format); err != nil {
+		return err
+	}
+	if hasLintError(diagnostics) {
+		return cli.Exit("", 1)
+	}
+
+	return nil
+}
+
+// writeLintDiagnostics print diagnostics in format
+func writeLintDiagnostics(w io.Writer, diagnostics []*LintDiagnostic, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diagnostics)
+	case "sarif":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(newSarifLog(diagnostics))
+	}
+
+	if len(diagnostics) == 0 {
+		fmt.Fprintln(w, "No problems found.")
+		return nil
+	}
+	countError := 0
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintf(w, "%s: %s: %s (%s)\n", diagnostic.Range, diagnostic.Severity, diagnostic.Message, diagnostic.Rule)
+		if diagnostic.Severity == LINT_SEVERITY_ERROR {
+			countError++
+		}
+	}
+	fmt.Fprintf(w, "%d errors, %d warnings\n", countError, len(diagnostics)-countError)
+
+	return nil
+}
+
+// sarifLog is minimal SARIF 2.1.0 log for code scanning and editors
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool      `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Version        string       `json:"version,omitempty"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string           `json:"ruleId"`
+	Level     string           `json:"level"`
+	Message   sarifMessage     `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// newSarifLog convert diagnostics into SARIF log with all rules
+func newSarifLog(diagnostics []*LintDiagnostic) *sarifLog {
+	run := &sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "tfcvars",
+				InformationURI: "https://github.com/thaim/tfcvars",
+				Version:        getVersion(),
+				Rules:          []*sarifRule{},
+			},
+		},
+		Results: []*sarifResult{},
+	}
+	for _, rule := range lintRules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: rule.Severity},
+		})
+	}
+	for _, diagnostic := range diagnostics {
+		run.Results = append(run.Results, &sarifResult{
+			RuleID:  diagnostic.Rule,
+			Level:   diagnostic.Severity,
+			Message: sarifMessage{Text: diagnostic.Message},
+			Locations: []*sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: diagnostic.Range.Filename},
+						Region: sarifRegion{
+							StartLine:   diagnostic.Range.StartLine,
+							StartColumn: diagnostic.Range.StartColumn,
+							EndLine:     diagnostic.Range.EndLine,
+							EndColumn:   diagnostic.Range.EndColumn,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return &sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []*sarifRun{run},
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+func TestWriteLintDiagnostics(t *testing.T) {
+	diagnostics := []*LintDiagnostic{
+		{
+			Rule:     LINT_RULE_DUPLICATE_KEY,
+			Severity: LINT_SEVERITY_ERROR,
+			Message:  "The argument \"db_port\" was already set at terraform.tfvars:1,1-8.",
+			Range:    LintRange{Filename: "terraform.tfvars", StartLine: 2, StartColumn: 1, EndLine: 2, EndColumn: 8},
+		},
+		{
+			Rule:     LINT_RULE_PLAINTEXT_SECRET,
+			Severity: LINT_SEVERITY_WARNING,
+			Message:  "value of 'db_password' looks secret",
+			Range:    LintRange{Filename: "terraform.tfvars", StartLine: 3, StartColumn: 1, EndLine: 3, EndColumn: 25},
+		},
+	}
+
+	cases := []struct {
+		name        string
+		diagnostics []*LintDiagnostic
+		expect      string
+	}{
+		{
+			name:        "no problem",
+			diagnostics: []*LintDiagnostic{},
+			expect:      "No problems found.\n",
+		},
+		{
+			name:        "errors and warnings",
+			diagnostics: diagnostics,
+			expect: `terraform.tfvars:2,1-8: error: The argument "db_port" was already set at terraform.tfvars:1,1-8. (duplicate-key)
+terraform.tfvars:3,1-25: warning: value of 'db_password' looks secret (plaintext-secret)
+1 errors, 1 warnings
+`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			outBuf := new(bytes.Buffer)
+
+			err := writeLintDiagnostics(outBuf, tt.diagnostics, "text")
+
+			if err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+			if outBuf.String() != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, outBuf.String())
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		outBuf := new(bytes.Buffer)
+		if err := writeLintDiagnostics(outBuf, diagnostics, "json"); err != nil {
+			t.Fatalf("expect no error, got error: %v", err)
+		}
+
+		actual := []*LintDiagnostic{}
+		if err := json.Unmarshal(outBuf.Bytes(), &actual); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		if !reflect.DeepEqual(diagnostics, actual) {
+			t.Errorf("expect '%+v', got '%+v'", diagnostics, actual)
+		}
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		outBuf := new(bytes.Buffer)
+		if err := writeLintDiagnostics(outBuf, diagnostics, "sarif"); err != nil {
+			t.Fatalf("expect no error, got error: %v", err)
+		}
+
+		actual := &sarifLog{}
+		if err := json.Unmarshal(outBuf.Bytes(), actual); err != nil {
+			t.Fatalf("invalid sarif: %v", err)
+		}
+		if actual.Version != "2.1.0" || len(actual.Runs) != 1 {
+			t.Fatalf("unexpected sarif log '%+v'", actual)
+		}
+		run := actual.Runs[0]
+		if len(run.Tool.Driver.Rules) != len(lintRules) {
+			t.Errorf("expect %d rules, got %d", len(lintRules), len(run.Tool.Driver.Rules))
+		}
+		if len(run.Results) != 2 {
+			t.Fatalf("expect 2 results, got %d", len(run.Results))
+		}
+		result := run.Results[1]
+		region := result.Locations[0].PhysicalLocation.Region
+		if result.RuleID != LINT_RULE_PLAINTEXT_SECRET || result.Level != "warning" || region.StartLine != 3 || region.EndColumn != 25 {
+			t.Errorf("unexpected result '%+v' at '%+v'", result, region)
+		}
+	})
+}
+
+func TestNewLintOption(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		expect *LintOption
+	}{
+		{
+			name:   "default value",
+			args:   []string{},
+			expect: &LintOption{files: []string{"terraform.tfvars"}, format: "text", out: os.Stdout},
+		},
+		{
+			name:   "specify all options",
+			args:   []string{"--format", "sarif", "--remote", "production.tfvars", "staging.tfvars"},
+			expect: &LintOption{files: []string{"production.tfvars", "staging.tfvars"}, format: "sarif", remote: true, out: os.Stdout},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			app := cli.NewApp()
+			set := flagSet(lintFlags())
+			set.Parse(tt.args)
+			ctx := cli.NewContext(app, set, nil)
+
+			sut := NewLintOption(ctx)
+
+			if !reflect.DeepEqual(tt.expect, sut) {
+				t.Errorf("expect '%+v', got '%+v'", tt.expect, sut)
+			}
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"strings"
 
 	tfe "github.com/hashicorp/go-tfe"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -19,20 +23,41 @@ type PullOption struct {
 	prevVarfile        []byte
 	includeEnv         bool
 	includeVariableSet bool
+	sensitive          string
+	envFile            string
+	prevEnvFile        []byte
+	envOut             io.Writer
+	backup             bool
+	filter             *VariableFilter
+	check              bool
+	ageRecipients      []string
 }
 
 func NewPullOption(c *cli.Context) *PullOption {
 	var opt = &PullOption{}
 
 	opt.varFile = c.String("var-file")
-	opt.overwrite = !c.Bool("merge")
 	opt.prevVarfile = nil
 	opt.includeEnv = c.Bool("include-env")
 	opt.includeVariableSet = c.Bool("include-variable-set")
+	opt.sensitive = c.String("sensitive")
+	opt.envFile = c.String("env-file")
+	opt.backup = !c.Bool("no-backup")
+	opt.filter = NewVariableFilter(c, c.StringSlice("variable"))
+	// filtered pull implies merge, otherwise local variables not selected are deleted
+	opt.overwrite = !c.Bool("merge") && opt.filter == nil
+	opt.check = c.Bool("check")
+	opt.ageRecipients = c.StringSlice("age-recipient")
 
 	return opt
 }
 
+// String omit contents of previous var-file to keep values out of logs
+func (opt PullOption) String() string {
+	return fmt.Sprintf("{varFile:%s overwrite:%t prevVarfile:(%d bytes) includeEnv:%t includeVariableSet:%t sensitive:%s envFile:%s prevEnvFile:(%d bytes) backup:%t filter:%+v check:%t ageRecipients:%v}",
+		opt.varFile, opt.overwrite, len(opt.prevVarfile), opt.includeEnv, opt.includeVariableSet, opt.sensitive, opt.envFile, len(opt.prevEnvFile), opt.backup, opt.filter, opt.check, opt.ageRecipients)
+}
+
 func Pull(c *cli.Context) error {
 	ctx := context.Background()
 	log.Debug().Msg("pull command")
@@ -49,20 +74,72 @@ func Pull(c *cli.Context) error {
 		return err
 	}
 	pullOpt := NewPullOption(c)
-	if !pullOpt.overwrite {
-		src, _ := os.ReadFile(pullOpt.varFile)
+	if err := pullOpt.filter.Validate(); err != nil {
+		return err
+	}
+	if !pullOpt.overwrite || pullOpt.sensitive == SENSITIVE_KEEP_LOCAL || pullOpt.check {
+		src, err := readDecryptedFile(pullOpt.varFile)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
 		pullOpt.prevVarfile = src
 	}
+	if pullOpt.envFile != "" && (!pullOpt.overwrite || pullOpt.sensitive == SENSITIVE_KEEP_LOCAL || pullOpt.check) {
+		src, err := readDecryptedFile(pullOpt.envFile)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		pullOpt.prevEnvFile = src
+	}
+	if len(pullOpt.ageRecipients) == 0 && !pullOpt.check {
+		for _, filename := range []string{pullOpt.varFile, pullOpt.envFile} {
+			if requireEncryption(filename) {
+				return fmt.Errorf("%s is encrypted, specify --age-recipient to encrypt pulled variables", filename)
+			}
+		}
+	}
 	log.Debug().Msgf("pullOption: %+v", pullOpt)
 
-	f, err := os.Create(pullOpt.varFile)
+	if pullOpt.check {
+		err = pull(ctx, w.ID, tfeClient.Variables, tfeClient.VariableSets, tfeClient.VariableSetVariables, pullOpt, os.Stdout)
+		if errors.Is(err, errDrift) {
+			return cli.Exit("", 2)
+		}
+		return err
+	}
+
+	// render all contents before touching local files
+	var buf bytes.Buffer
+	var envBuf bytes.Buffer
+	pullOpt.envOut = &envBuf
+	err = pull(ctx, w.ID, tfeClient.Variables, tfeClient.VariableSets, tfeClient.VariableSetVariables, pullOpt, &buf)
 	if err != nil {
-		log.Error().Err(err).Msgf("cannot open varfile: %s", pullOpt.varFile)
 		return err
 	}
-	defer f.Close()
 
-	return pull(ctx, w.ID, tfeClient.Variables, tfeClient.VariableSets, tfeClient.VariableSetVariables, pullOpt, f)
+	content, err := encryptOutput(buf.Bytes(), pullOpt.ageRecipients)
+	if err != nil {
+		return err
+	}
+	envContent, err := encryptOutput(envBuf.Bytes(), pullOpt.ageRecipients)
+	if err != nil {
+		return err
+	}
+
+	err = writeFileAtomic(pullOpt.varFile, content, pullOpt.backup)
+	if err != nil {
+		log.Error().Err(err).Msgf("cannot write varfile: %s", pullOpt.varFile)
+		return err
+	}
+	if pullOpt.envFile != "" {
+		err = writeFileAtomic(pullOpt.envFile, envContent, pullOpt.backup)
+		if err != nil {
+			log.Error().Err(err).Msgf("cannot write env file: %s", pullOpt.envFile)
+			return err
+		}
+	}
+
+	return nil
 }
 
 func pull(ctx context.Context, workspaceId string, tfeVariables tfe.Variables, tfeVariableSets tfe.VariableSets, tfeVariableSetVariables tfe.VariableSetVariables, pullOpt *PullOption, w io.Writer) error {
@@ -79,7 +156,22 @@ func pull(ctx context.Context, workspaceId string, tfeVariables tfe.Variables, t
 		}
 		vars.Items = append(vars.Items, variableSetVariables...)
 	}
-	if !pullOpt.includeEnv {
+	vars.Items = pullOpt.filter.Filter(vars.Items)
+	sensitive := sensitiveKeys(vars.Items)
+
+	var envContent []byte
+	if pullOpt.envFile != "" {
+		// env category variables are written into env file instead of var-file
+		envVars := vars.Items
+		vars.Items = FilterEnv(vars.Items)
+
+		sensitiveOpt := &SensitiveOption{strategy: pullOpt.sensitive}
+		if pullOpt.overwrite {
+			envContent = BuildDotenvFile(envVars, sensitiveOpt, pullOpt.prevEnvFile)
+		} else {
+			envContent = MergeDotenvFile(envVars, sensitiveOpt, pullOpt.prevEnvFile)
+		}
+	} else if !pullOpt.includeEnv && !pullOpt.filter.requireEnv() {
 		vars.Items = FilterEnv(vars.Items)
 	}
 
@@ -88,16 +180,52 @@ func pull(ctx context.Context, workspaceId string, tfeVariables tfe.Variables, t
 		base = pullOpt.prevVarfile
 	}
 
-	f, err := BuildHCLFile(vars.Items, base, pullOpt.varFile)
+	sensitiveOpt := NewSensitiveOption(pullOpt.sensitive, pullOpt.varFile, pullOpt.prevVarfile)
+	f, err := BuildHCLFile(vars.Items, base, pullOpt.varFile, sensitiveOpt)
 	if err != nil {
 		return err
 	}
 
+	if pullOpt.check {
+		redactTfvars := func(src []byte) []byte {
+			return (&Tfvars{filename: pullOpt.varFile, vardata: src}).redact(sensitive).vardata
+		}
+		changed := reportFileChange(w, pullOpt.varFile, pullOpt.prevVarfile, f.Bytes(), redactTfvars)
+		if pullOpt.envFile != "" {
+			redactEnv := func(src []byte) []byte {
+				return redactDotenv(src, sensitive)
+			}
+			changed = reportFileChange(w, pullOpt.envFile, pullOpt.prevEnvFile, envContent, redactEnv) || changed
+		}
+		if changed {
+			return errDrift
+		}
+		return nil
+	}
+
 	fmt.Fprintf(w, "%s", f.Bytes())
+	if pullOpt.envFile != "" {
+		fmt.Fprintf(pullOpt.envOut, "%s", envContent)
+	}
 
 	return nil
 }
 
+// reportFileChange print whether local file would be changed with the difference masked by redact
+// return true if the file would be changed
+func reportFileChange(w io.Writer, filename string, current []byte, rendered []byte, redact func([]byte) []byte) bool {
+	if bytes.Equal(current, rendered) {
+		fmt.Fprintf(w, "%s is up to date\n", filename)
+		return false
+	}
+
+	fmt.Fprintf(w, "%s would be changed\n", filename)
+	_, diffString := fileDiff(string(redact(current)), string(redact(rendered)))
+	fmt.Fprint(w, diffString)
+
+	return true
+}
+
 func generateComment(key string) hclwrite.Tokens {
 	tokens := hclwrite.Tokens{
 		{
@@ -124,3 +252,17 @@ func generateComment(key string) hclwrite.Tokens {
 
 	return tokens
 }
+
+// generateDescriptionComment return '#' comment lines of description
+func generateDescriptionComment(description string) hclwrite.Tokens {
+	tokens := hclwrite.Tokens{}
+
+	for _, line := range strings.Split(description, "\n") {
+		tokens = append(tokens, &hclwrite.Token{
+			Type:  hclsyntax.TokenComment,
+			Bytes: []byte(strings.TrimRight("# "+line, " ") + "\n"),
+		})
+	}
+
+	return tokens
+}
@@ -26,6 +26,7 @@ func TestCmdPull(t *testing.T) {
 		pullOpt     *PullOption
 		setClient   func(*mocks.MockVariables, *mocks.MockVariableSets, *mocks.MockVariableSetVariables)
 		expect      string
+		expectEnv   string
 		wantErr     bool
 		expectErr   string
 	}{
@@ -63,7 +64,7 @@ func TestCmdPull(t *testing.T) {
 					}, nil).
 					AnyTimes()
 			},
-			expect:    "var1 = \"value1\"\n",
+			expect:    "# description1\nvar1 = \"value1\"\n",
 			wantErr:   false,
 			expectErr: "",
 		},
@@ -118,7 +119,7 @@ func TestCmdPull(t *testing.T) {
 					}, nil).
 					AnyTimes()
 			},
-			expect:    "// var1 = \"***\"\n",
+			expect:    "# sensitive\n// var1 = \"***\"\n",
 			wantErr:   false,
 			expectErr: "",
 		},
@@ -150,7 +151,7 @@ func TestCmdPull(t *testing.T) {
 					}, nil).
 					AnyTimes()
 			},
-			expect:    "var1 = \"value1\"\n",
+			expect:    "# Terraform Variables\nvar1 = \"value1\"\n",
 			wantErr:   false,
 			expectErr: "",
 		},
@@ -301,6 +302,421 @@ func TestCmdPull(t *testing.T) {
 			wantErr:   false,
 			expectErr: "",
 		},
+		{
+			name:        "write sensitive variable as comment",
+			workspaceId: "w-test-sensitive-comment",
+			pullOpt:     &PullOption{sensitive: SENSITIVE_COMMENT},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-sensitive-comment", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "test",
+							},
+							{
+								Key:       "db_password",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "environment = \"test\"\n// db_password = \"***\"\n",
+		},
+		{
+			name:        "omit sensitive variable",
+			workspaceId: "w-test-sensitive-omit",
+			pullOpt:     &PullOption{sensitive: SENSITIVE_OMIT, prevVarfile: []byte("db_password = \"supersecret\"\n")},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-sensitive-omit", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "test",
+							},
+							{
+								Key:       "db_password",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "environment = \"test\"\n",
+		},
+		{
+			name:        "remove local value of sensitive variable with omit and merge",
+			workspaceId: "w-test-sensitive-omit-merge",
+			pullOpt:     &PullOption{sensitive: SENSITIVE_OMIT, prevVarfile: []byte("region = \"ap-northeast-1\"\ndb_password = \"supersecret\"\n")},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-sensitive-omit-merge", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "test",
+							},
+							{
+								Key:       "db_password",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "region      = \"ap-northeast-1\"\nenvironment = \"test\"\n",
+		},
+		{
+			name:        "replace local value of sensitive variable with comment and merge",
+			workspaceId: "w-test-sensitive-comment-merge",
+			pullOpt:     &PullOption{sensitive: SENSITIVE_COMMENT, prevVarfile: []byte("region = \"ap-northeast-1\"\ndb_password = \"supersecret\"\n")},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-sensitive-comment-merge", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:       "db_password",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "region = \"ap-northeast-1\"\n// db_password = \"***\"\n",
+		},
+		{
+			name:        "replace local value of sensitive variable with placeholder and merge",
+			workspaceId: "w-test-sensitive-placeholder-merge",
+			pullOpt:     &PullOption{sensitive: SENSITIVE_PLACEHOLDER, prevVarfile: []byte("db_password = \"supersecret\"\n")},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-sensitive-placeholder-merge", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:       "db_password",
+								Sensitive: true,
+							},
+							{
+								Key:       "api_token",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "db_password = \"\"\napi_token   = \"\"\n",
+		},
+		{
+			name:        "keep local value of sensitive variable with merge",
+			workspaceId: "w-test-sensitive-keep-local-merge",
+			pullOpt:     &PullOption{sensitive: SENSITIVE_KEEP_LOCAL, prevVarfile: []byte("# database\ndb_password = \"supersecret\"\n")},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-sensitive-keep-local-merge", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "test",
+							},
+							{
+								Key:       "db_password",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "# database\ndb_password = \"supersecret\"\nenvironment = \"test\"\n",
+		},
+		{
+			name:        "keep local value of sensitive variable with overwrite",
+			workspaceId: "w-test-sensitive-keep-local-overwrite",
+			pullOpt:     &PullOption{overwrite: true, sensitive: SENSITIVE_KEEP_LOCAL, prevVarfile: []byte("region = \"ap-northeast-1\"\ndb_password = \"supersecret\"\n")},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-sensitive-keep-local-overwrite", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "test",
+							},
+							{
+								Key:       "db_password",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "environment = \"test\"\ndb_password = \"supersecret\"\n",
+		},
+		{
+			name:        "write comment if local value of sensitive variable not exist",
+			workspaceId: "w-test-sensitive-keep-local-not-exist",
+			pullOpt:     &PullOption{sensitive: SENSITIVE_KEEP_LOCAL},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-sensitive-keep-local-not-exist", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "test",
+							},
+							{
+								Key:       "db_password",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "environment = \"test\"\n// db_password = \"***\"\n",
+		},
+		{
+			name:        "write placeholder of sensitive variable",
+			workspaceId: "w-test-sensitive-placeholder",
+			pullOpt:     &PullOption{varFile: "testdata/config/terraform.tfvars", overwrite: true, sensitive: SENSITIVE_PLACEHOLDER},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-sensitive-placeholder", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "test",
+							},
+							{
+								Key:       "db_password",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "environment = \"test\"\ndb_password = \"\"\n",
+		},
+		{
+			name:        "merge only selected variables into existing file",
+			workspaceId: "w-test-filter-workspace",
+			pullOpt: &PullOption{
+				prevVarfile: []byte("environment = \"development\"\ndb_host = \"localhost\"\n"),
+				filter:      &VariableFilter{includes: []string{"db_*"}},
+			},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-filter-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "production",
+							},
+							{
+								Key:   "db_host",
+								Value: "db.example.com",
+							},
+							{
+								Key:   "db_port",
+								Value: "5432",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "environment = \"development\"\ndb_host     = \"db.example.com\"\ndb_port     = \"5432\"\n",
+		},
+		{
+			name:        "pull env category with category filter",
+			workspaceId: "w-test-filter-category-workspace",
+			pullOpt: &PullOption{
+				overwrite: true,
+				filter:    &VariableFilter{category: "env"},
+			},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-filter-category-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:      "environment",
+								Value:    "production",
+								Category: tfe.CategoryTerraform,
+							},
+							{
+								Key:      "AWS_REGION",
+								Value:    "ap-northeast-1",
+								Category: tfe.CategoryEnv,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "AWS_REGION = \"ap-northeast-1\"\n",
+		},
+		{
+			name:        "write description as comment only for new variables",
+			workspaceId: "w-test-description-workspace",
+			pullOpt: &PullOption{
+				prevVarfile: []byte("# my own comment\nenvironment = \"development\"\n"),
+			},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-description-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:         "environment",
+								Value:       "production",
+								Description: "Environment name",
+							},
+							{
+								Key:         "region",
+								Value:       "ap-northeast-1",
+								Description: "AWS region\nused by provider",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "# my own comment\nenvironment = \"production\"\n# AWS region\n# used by provider\nregion = \"ap-northeast-1\"\n",
+		},
+		{
+			name:        "report no change in check mode",
+			workspaceId: "w-test-check-no-change-workspace",
+			pullOpt: &PullOption{
+				varFile:     "terraform.tfvars",
+				overwrite:   true,
+				check:       true,
+				prevVarfile: []byte("environment = \"test\"\n"),
+			},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-check-no-change-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "test",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: "terraform.tfvars is up to date\n",
+		},
+		{
+			name:        "return drift error in check mode",
+			workspaceId: "w-test-check-changed-workspace",
+			pullOpt: &PullOption{
+				varFile:     "terraform.tfvars",
+				overwrite:   true,
+				check:       true,
+				prevVarfile: []byte("environment = \"development\"\n"),
+			},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-check-changed-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "environment",
+								Value: "test",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			wantErr:   true,
+			expectErr: "drift detected",
+		},
+		{
+			name:        "pull env variables into env file",
+			workspaceId: "w-test-env-file-workspace",
+			pullOpt:     &PullOption{envFile: ".env"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-env-file-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:      "var1",
+								Value:    "value1",
+								Category: tfe.CategoryTerraform,
+							},
+							{
+								Key:      "AWS_REGION",
+								Value:    "ap-northeast-1",
+								Category: tfe.CategoryEnv,
+							},
+							{
+								Key:       "AWS_SECRET_ACCESS_KEY",
+								Category:  tfe.CategoryEnv,
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "var1 = \"value1\"\n",
+			expectEnv: "AWS_REGION=\"ap-northeast-1\"\n# AWS_SECRET_ACCESS_KEY=***\n",
+		},
+		{
+			name:        "merge env variables into existing env file",
+			workspaceId: "w-test-env-file-merge",
+			pullOpt:     &PullOption{envFile: ".env", prevEnvFile: []byte("# local settings\nAWS_PROFILE=dev\nAWS_REGION=us-east-1\n")},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-env-file-merge", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:      "AWS_REGION",
+								Value:    "ap-northeast-1",
+								Category: tfe.CategoryEnv,
+							},
+							{
+								Key:       "AWS_SECRET_ACCESS_KEY",
+								Category:  tfe.CategoryEnv,
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "",
+			expectEnv: "# local settings\nAWS_PROFILE=dev\nAWS_REGION=\"ap-northeast-1\"\n# AWS_SECRET_ACCESS_KEY=***\n",
+		},
+		{
+			name:        "overwrite existing env file",
+			workspaceId: "w-test-env-file-overwrite",
+			pullOpt:     &PullOption{overwrite: true, envFile: ".env", prevEnvFile: []byte("AWS_PROFILE=dev\n")},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-env-file-overwrite", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:      "AWS_REGION",
+								Value:    "ap-northeast-1",
+								Category: tfe.CategoryEnv,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "",
+			expectEnv: "AWS_REGION=\"ap-northeast-1\"\n",
+		},
 	}
 
 	for _, tt := range cases {
@@ -308,6 +724,8 @@ func TestCmdPull(t *testing.T) {
 			ctx := context.TODO()
 			tt.setClient(mockVariables, mockVariableSets, mockVariableSetVariables)
 			var buf bytes.Buffer
+			var envBuf bytes.Buffer
+			tt.pullOpt.envOut = &envBuf
 
 			err := pull(ctx, tt.workspaceId, mockVariables, mockVariableSets, mockVariableSetVariables, tt.pullOpt, &buf)
 
@@ -325,6 +743,59 @@ func TestCmdPull(t *testing.T) {
 			if bufString := buf.String(); bufString != tt.expect {
 				t.Errorf("expect '%s', got '%s'", tt.expect, buf.String())
 			}
+			if envString := envBuf.String(); envString != tt.expectEnv {
+				t.Errorf("expect env '%s', got '%s'", tt.expectEnv, envString)
+			}
+		})
+	}
+}
+
+func TestReportFileChange(t *testing.T) {
+	cases := []struct {
+		name          string
+		current       []byte
+		rendered      []byte
+		expect        string
+		expectChanged bool
+	}{
+		{
+			name:          "file not changed",
+			current:       []byte("environment = \"test\"\n"),
+			rendered:      []byte("environment = \"test\"\n"),
+			expect:        "terraform.tfvars is up to date\n",
+			expectChanged: false,
+		},
+		{
+			name:          "file not exist and no variables",
+			current:       nil,
+			rendered:      []byte{},
+			expect:        "terraform.tfvars is up to date\n",
+			expectChanged: false,
+		},
+		{
+			name:          "mask sensitive values in difference",
+			current:       []byte("db_password = \"supersecret\"\n"),
+			rendered:      []byte("// db_password = \"***\"\n"),
+			expect:        "terraform.tfvars would be changed\n- db_password = \"(sensitive)\"\n+ // db_password = \"***\"\n",
+			expectChanged: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			redact := func(src []byte) []byte {
+				return (&Tfvars{filename: "terraform.tfvars", vardata: src}).redact(map[string]bool{"db_password": true}).vardata
+			}
+
+			changed := reportFileChange(&buf, "terraform.tfvars", tt.current, tt.rendered, redact)
+
+			if changed != tt.expectChanged {
+				t.Errorf("expect changed '%t', got '%t'", tt.expectChanged, changed)
+			}
+			if actual := replaceNBSPWithSpace(buf.String()); actual != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, actual)
+			}
 		})
 	}
 }
@@ -342,6 +813,8 @@ func TestNewPullOption(t *testing.T) {
 				varFile:     "terraform.tfvars",
 				overwrite:   true,
 				prevVarfile: nil,
+				sensitive:   "comment",
+				backup:      true,
 			},
 		},
 		{
@@ -351,6 +824,8 @@ func TestNewPullOption(t *testing.T) {
 				varFile:     "custom.tfvars",
 				overwrite:   true,
 				prevVarfile: nil,
+				sensitive:   "comment",
+				backup:      true,
 			},
 		},
 		{
@@ -360,6 +835,8 @@ func TestNewPullOption(t *testing.T) {
 				varFile:     "terraform.tfvars",
 				overwrite:   true,
 				prevVarfile: nil,
+				sensitive:   "comment",
+				backup:      true,
 			},
 		},
 		{
@@ -369,6 +846,8 @@ func TestNewPullOption(t *testing.T) {
 				varFile:     "terraform.tfvars",
 				overwrite:   false,
 				prevVarfile: nil,
+				sensitive:   "comment",
+				backup:      true,
 			},
 		},
 		{
@@ -378,6 +857,8 @@ func TestNewPullOption(t *testing.T) {
 				varFile:     "terraform.tfvars",
 				overwrite:   false,
 				prevVarfile: nil,
+				sensitive:   "comment",
+				backup:      true,
 			},
 		},
 		{
@@ -387,6 +868,29 @@ func TestNewPullOption(t *testing.T) {
 				varFile:    "terraform.tfvars",
 				overwrite:  true,
 				includeEnv: true,
+				sensitive:  "comment",
+				backup:     true,
+			},
+		},
+		{
+			name: "enable check option",
+			args: []string{"--check"},
+			expect: &PullOption{
+				varFile:   "terraform.tfvars",
+				overwrite: true,
+				sensitive: "comment",
+				backup:    true,
+				check:     true,
+			},
+		},
+		{
+			name: "disable backup",
+			args: []string{"--no-backup"},
+			expect: &PullOption{
+				varFile:   "terraform.tfvars",
+				overwrite: true,
+				sensitive: "comment",
+				backup:    false,
 			},
 		},
 		{
@@ -396,6 +900,30 @@ func TestNewPullOption(t *testing.T) {
 				varFile:            "terraform.tfvars",
 				overwrite:          true,
 				includeVariableSet: true,
+				sensitive:          "comment",
+				backup:             true,
+			},
+		},
+		{
+			name: "merge if variables are filtered",
+			args: []string{"--overwrite", "--variable", "environment"},
+			expect: &PullOption{
+				varFile:   "terraform.tfvars",
+				overwrite: false,
+				sensitive: "comment",
+				backup:    true,
+				filter:    &VariableFilter{keys: []string{"environment"}},
+			},
+		},
+		{
+			name: "encrypt for age recipients",
+			args: []string{"--age-recipient", "age1alice", "--age-recipient", "age1bob"},
+			expect: &PullOption{
+				varFile:       "terraform.tfvars",
+				overwrite:     true,
+				sensitive:     "comment",
+				backup:        true,
+				ageRecipients: []string{"age1alice", "age1bob"},
 			},
 		},
 	}
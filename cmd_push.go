@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,28 +15,37 @@ import (
 )
 
 type PushOption struct {
-	varFile       string
-	variableKey   string
-	variableValue string
-	delete        bool
-	autoApprove   bool
-	in            io.Reader
-	out           io.Writer
+	varFile          string
+	variables        []*tfe.Variable // values specified with --variable KEY=VALUE instead of var-file
+	delete           bool
+	autoApprove      bool
+	interactive      bool
+	syncDescriptions bool
+	filter           *VariableFilter
+	remoteSensitive  map[string]bool
+	in               io.Reader
+	out              io.Writer
 }
 
 func NewPushOption(c *cli.Context) *PushOption {
 	var opt = &PushOption{}
 	opt.varFile = c.String("var-file")
 
-	variable := c.String("variable")
-	if variable != "" {
-		splitVariable := strings.SplitN(variable, "=", 2)
-		opt.variableKey = splitVariable[0]
-		opt.variableValue = splitVariable[1]
+	// --variable KEY selects variable in var-file, and --variable KEY=VALUE pushes the value
+	keys := []string{}
+	for _, variable := range c.StringSlice("variable") {
+		key, value, found := strings.Cut(variable, "=")
+		if found {
+			opt.variables = append(opt.variables, BuildVariableList(key, value).Items...)
+		}
+		keys = append(keys, key)
 	}
 
 	opt.delete = c.Bool("delete")
 	opt.autoApprove = c.Bool("auto-approve")
+	opt.interactive = c.Bool("interactive")
+	opt.syncDescriptions = c.Bool("sync-descriptions")
+	opt.filter = NewVariableFilter(c, keys)
 
 	opt.in = os.Stdin
 	opt.out = os.Stdout
@@ -43,6 +53,52 @@ func NewPushOption(c *cli.Context) *PushOption {
 	return opt
 }
 
+// Validate check filter, which cannot select env category variables since var-file has none of them
+func (opt *PushOption) Validate() error {
+	if opt.filter.requireEnv() {
+		return errors.New("--category env is not supported by push command since env variables are not read from var-file")
+	}
+
+	return opt.filter.Validate()
+}
+
+// String omit values specified with --variable option to keep them out of logs
+func (opt PushOption) String() string {
+	keys := []string{}
+	for _, v := range opt.variables {
+		keys = append(keys, v.Key)
+	}
+
+	return fmt.Sprintf("{varFile:%s variables:%v delete:%t autoApprove:%t interactive:%t syncDescriptions:%t filter:%+v}",
+		opt.varFile, keys, opt.delete, opt.autoApprove, opt.interactive, opt.syncDescriptions, opt.filter)
+}
+
+// localVariables return variables to push
+// var-file is read unless all variables are specified with values, and specified values take precedence
+func (opt *PushOption) localVariables() ([]*tfe.Variable, error) {
+	if len(opt.variables) != 0 && opt.filter != nil && len(opt.variables) == len(opt.filter.keys) {
+		return opt.variables, nil
+	}
+
+	vf, err := NewTfvarsFile(opt.varFile)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to parse tfvars file")
+		return nil, err
+	}
+	vars := []*tfe.Variable{}
+	for _, v := range vf.vars {
+		specified := false
+		for _, value := range opt.variables {
+			specified = specified || value.Key == v.Key
+		}
+		if !specified {
+			vars = append(vars, v)
+		}
+	}
+
+	return append(vars, opt.variables...), nil
+}
+
 const (
 	PUSH_OPERATION_CREATE = "create"
 	PUSH_OPERATION_UPDATE = "update"
@@ -52,10 +108,136 @@ const (
 type PushVariable struct {
 	operation    string
 	id           string
+	previous     *tfe.Variable
 	createOption tfe.VariableCreateOptions
 	updateOption tfe.VariableUpdateOptions
 }
 
+// key return variable key which the operation targets
+func (v *PushVariable) key() string {
+	switch v.operation {
+	case PUSH_OPERATION_CREATE:
+		return *v.createOption.Key
+	case PUSH_OPERATION_UPDATE:
+		return *v.updateOption.Key
+	}
+
+	if v.previous != nil {
+		return v.previous.Key
+	}
+	return ""
+}
+
+// sensitive return true if the variable is sensitive on either side
+func (v *PushVariable) sensitive() bool {
+	if v.previous != nil && v.previous.Sensitive {
+		return true
+	}
+
+	switch v.operation {
+	case PUSH_OPERATION_CREATE:
+		return v.createOption.Sensitive != nil && *v.createOption.Sensitive
+	case PUSH_OPERATION_UPDATE:
+		return v.updateOption.Sensitive != nil && *v.updateOption.Sensitive
+	}
+
+	return false
+}
+
+// newPushUpdate return operation to update remote variable with local one
+// local file cannot specify sensitive, hcl and category, so they are kept as remote ones,
+// and description is updated only if syncDescriptions is enabled and local variable has comment
+func newPushUpdate(local *tfe.Variable, remote *tfe.Variable, syncDescriptions bool) *PushVariable {
+	updateOpt := tfe.VariableUpdateOptions{
+		Key:         tfe.String(local.Key),
+		Value:       tfe.String(local.Value),
+		Description: tfe.String(remote.Description),
+		Category:    tfe.Category(remote.Category),
+		HCL:         tfe.Bool(remote.HCL),
+		Sensitive:   tfe.Bool(remote.Sensitive),
+	}
+	if syncDescriptions && local.Description != "" {
+		updateOpt.Description = tfe.String(local.Description)
+	}
+
+	return &PushVariable{
+		operation:    PUSH_OPERATION_UPDATE,
+		id:           remote.ID,
+		previous:     remote,
+		updateOption: updateOpt,
+	}
+}
+
+// metadataChanges return changes of attributes other than value by update operation
+func (v *PushVariable) metadataChanges() []MetadataChange {
+	if v.operation != PUSH_OPERATION_UPDATE || v.previous == nil {
+		return nil
+	}
+
+	return variableMetadataChanges(v.previous, v.result())
+}
+
+// result return attributes of variable after the operation, or nil if deleted
+// value is left as previous one if not specified by the operation
+func (v *PushVariable) result() *tfe.Variable {
+	switch v.operation {
+	case PUSH_OPERATION_CREATE:
+		after := &tfe.Variable{Key: *v.createOption.Key, Category: tfe.CategoryTerraform}
+		if v.createOption.Value != nil {
+			after.Value = *v.createOption.Value
+		}
+		if v.createOption.Description != nil {
+			after.Description = *v.createOption.Description
+		}
+		if v.createOption.Category != nil {
+			after.Category = *v.createOption.Category
+		}
+		if v.createOption.HCL != nil {
+			after.HCL = *v.createOption.HCL
+		}
+		if v.createOption.Sensitive != nil {
+			after.Sensitive = *v.createOption.Sensitive
+		}
+		return after
+	case PUSH_OPERATION_UPDATE:
+		after := &tfe.Variable{}
+		if v.previous != nil {
+			*after = *v.previous
+		}
+		if v.updateOption.Key != nil {
+			after.Key = *v.updateOption.Key
+		}
+		if v.updateOption.Value != nil {
+			after.Value = *v.updateOption.Value
+		}
+		if v.updateOption.Sensitive != nil {
+			after.Sensitive = *v.updateOption.Sensitive
+		}
+		if v.updateOption.HCL != nil {
+			after.HCL = *v.updateOption.HCL
+		}
+		if v.updateOption.Category != nil {
+			after.Category = *v.updateOption.Category
+		}
+		if v.updateOption.Description != nil {
+			after.Description = *v.updateOption.Description
+		}
+		return after
+	}
+
+	return nil
+}
+
+// setValue replace value to be pushed
+func (v *PushVariable) setValue(value string) {
+	switch v.operation {
+	case PUSH_OPERATION_CREATE:
+		v.createOption.Value = tfe.String(value)
+	case PUSH_OPERATION_UPDATE:
+		v.updateOption.Value = tfe.String(value)
+	}
+}
+
 func Push(c *cli.Context) error {
 	ctx := context.Background()
 	log.Debug().Msg("push command")
@@ -73,22 +255,20 @@ func Push(c *cli.Context) error {
 	}
 
 	pushOpt := NewPushOption(c)
+	if err := pushOpt.Validate(); err != nil {
+		return err
+	}
 	log.Debug().Msgf("pushOption: %+v", pushOpt)
-
-	vars := &tfe.VariableList{}
-	if pushOpt.variableKey == "" {
-		vf, err := NewTfvarsFile(pushOpt.varFile)
-		if err != nil {
-			log.Error().Err(err).Msg("failed to parse tfvars file")
-			return err
-		}
-		vars.Items = vf.vars
-	} else {
-		vars = BuildVariableList(pushOpt.variableKey, pushOpt.variableValue)
+	pushOpt.remoteSensitive, err = listVariableSetSensitiveKeys(ctx, w.ID, tfeClient.VariableSets, tfeClient.VariableSetVariables)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to check sensitive variables in variable sets")
 	}
+
+	localVars, err := pushOpt.localVariables()
 	if err != nil {
 		return err
 	}
+	vars := &tfe.VariableList{Items: localVars}
 
 	return push(ctx, w.ID, tfeClient.Variables, pushOpt, vars)
 }
@@ -99,7 +279,8 @@ func push(ctx context.Context, workspaceId string, tfeVariables tfe.Variables, p
 		log.Error().Err(err).Msg("failed to list variables")
 		return err
 	}
-	previousVars.Items = FilterEnv(previousVars.Items)
+	previousVars.Items = pushOpt.filter.Filter(FilterEnv(previousVars.Items))
+	vars = &tfe.VariableList{Items: pushOpt.filter.Filter(vars.Items)}
 
 	variables := []*PushVariable{}
 
@@ -108,20 +289,9 @@ func push(ctx context.Context, workspaceId string, tfeVariables tfe.Variables, p
 
 		for _, targetVar := range previousVars.Items {
 			if targetVar.Key == variable.Key {
-				updateOpt := tfe.VariableUpdateOptions{
-					Key:         tfe.String(variable.Key),
-					Value:       tfe.String(variable.Value),
-					Description: tfe.String(targetVar.Description),
-					Category:    tfe.Category(targetVar.Category),
-					HCL:         tfe.Bool(targetVar.HCL),
-					Sensitive:   tfe.Bool(targetVar.Sensitive),
-				}
-				if !variableEqual(updateOpt, targetVar) {
-					variables = append(variables, &PushVariable{
-						operation:    PUSH_OPERATION_UPDATE,
-						id:           targetVar.ID,
-						updateOption: updateOpt,
-					})
+				update := newPushUpdate(variable, targetVar, pushOpt.syncDescriptions)
+				if !variableEqual(update.updateOption, targetVar) {
+					variables = append(variables, update)
 				}
 				pushed = true
 			}
@@ -135,6 +305,9 @@ func push(ctx context.Context, workspaceId string, tfeVariables tfe.Variables, p
 				HCL:       tfe.Bool(false),
 				Sensitive: tfe.Bool(false),
 			}
+			if pushOpt.syncDescriptions && variable.Description != "" {
+				createOpt.Description = tfe.String(variable.Description)
+			}
 			variables = append(variables, &PushVariable{
 				operation:    PUSH_OPERATION_CREATE,
 				createOption: createOpt,
@@ -143,29 +316,51 @@ func push(ctx context.Context, workspaceId string, tfeVariables tfe.Variables, p
 	}
 
 	if pushOpt.delete {
+	remoteVariableLoop:
 		for _, targetVar := range previousVars.Items {
 			for _, localVar := range vars.Items {
 				if targetVar.Key == localVar.Key {
-					continue
+					continue remoteVariableLoop
 				}
-
-				// variable that are defined in remote but not in local
-				variables = append(variables, &PushVariable{
-					operation: PUSH_OPERATION_DELETE,
-					id:        targetVar.ID,
-				})
 			}
+
+			// variable that are defined in remote but not in local
+			variables = append(variables, &PushVariable{
+				operation: PUSH_OPERATION_DELETE,
+				id:        targetVar.ID,
+				previous:  targetVar,
+			})
 		}
 	}
 
-	if !pushOpt.autoApprove {
-		vfSrc := NewTfvarsVariable(previousVars.Items)
-		vfDest := NewTfvarsVariable(vars.Items)
+	for _, key := range plaintextSensitiveKeys(variables, pushOpt.remoteSensitive) {
+		fmt.Fprintf(pushOpt.out, "Warning: '%s' is sensitive in Terraform Cloud but will be uploaded as non-sensitive\n", key)
+	}
+
+	if pushOpt.interactive && !pushOpt.autoApprove {
+		variables, err = interactiveApprove(pushOpt.in, pushOpt.out, variables)
+		if err != nil {
+			return err
+		}
+	} else if !pushOpt.autoApprove {
+		sensitive := sensitiveKeys(previousVars.Items, vars.Items)
+		for key := range pushOpt.remoteSensitive {
+			sensitive[key] = true
+		}
+		vfSrc := NewTfvarsVariable(redactVariables(previousVars.Items, sensitive))
+		vfDest := NewTfvarsVariable(redactVariables(alignEquivalentValues(vars.Items, previousVars.Items), sensitive))
 		includeDiff, diffString := fileDiff(vfSrc.BuildHCLFileString(), vfDest.BuildHCLFileString())
-		if !includeDiff {
+		metadataDiffString := describeMetadataChanges(variables)
+		if !includeDiff && metadataDiffString == "" {
 			return nil
 		}
-		fmt.Fprint(pushOpt.out, diffString)
+		if includeDiff {
+			fmt.Fprint(pushOpt.out, diffString)
+		}
+		fmt.Fprint(pushOpt.out, metadataDiffString)
+		if err := requireTerminal(pushOpt.in); err != nil {
+			return err
+		}
 
 		fmt.Print("\nAre you sure you want to change variables in Terraform Cloud? [y/n]: ")
 		res, err := confirm(pushOpt.in)
@@ -177,32 +372,124 @@ func push(ctx context.Context, workspaceId string, tfeVariables tfe.Variables, p
 		}
 	}
 
-	countUpdate := 0
-	countCreate := 0
-	countDelete := 0
-	for _, variable := range variables {
+	return applyPushVariables(ctx, workspaceId, tfeVariables, variables, pushOpt.out)
+}
+
+// plaintextSensitiveKeys return keys of variables to be pushed as non-sensitive though they are sensitive remotely
+func plaintextSensitiveKeys(variables []*PushVariable, remoteSensitive map[string]bool) []string {
+	keys := []string{}
+	for _, v := range variables {
+		after := v.result()
+		if after == nil || after.Sensitive || after.Value == "" {
+			continue
+		}
+		if remoteSensitive[after.Key] {
+			keys = append(keys, after.Key)
+		}
+	}
+
+	return keys
+}
+
+// applyPushVariables apply planned operations to workspace
+// all operations are tried even if some of them fail, and failures are reported by key
+func applyPushVariables(ctx context.Context, workspaceId string, tfeVariables tfe.Variables, variables []*PushVariable, out io.Writer) error {
+	return applyOperations(JOURNAL_TARGET_WORKSPACE, workspaceId, variables, out, func(variable *PushVariable) (string, error) {
 		switch variable.operation {
 		case PUSH_OPERATION_CREATE:
-			tfeVariables.Create(ctx, workspaceId, variable.createOption)
-			countCreate++
+			created, err := tfeVariables.Create(ctx, workspaceId, variable.createOption)
+			if err != nil || created == nil {
+				return "", err
+			}
+			return created.ID, nil
 		case PUSH_OPERATION_UPDATE:
-			tfeVariables.Update(ctx, workspaceId, variable.id, variable.updateOption)
-			countUpdate++
+			_, err := tfeVariables.Update(ctx, workspaceId, variable.id, variable.updateOption)
+			return variable.id, err
 		case PUSH_OPERATION_DELETE:
-			tfeVariables.Delete(ctx, workspaceId, variable.id)
-			countDelete++
-		default:
-			return fmt.Errorf("unknown operation '%s'", variable.operation)
+			return variable.id, tfeVariables.Delete(ctx, workspaceId, variable.id)
 		}
+		return "", fmt.Errorf("unknown operation '%s'", variable.operation)
+	})
+}
+
+// applyVariableSetPushVariables apply planned operations to variable set
+// category of variable set variable cannot be updated
+func applyVariableSetPushVariables(ctx context.Context, variableSetId string, tfeVariableSetVariables tfe.VariableSetVariables, variables []*PushVariable, out io.Writer) error {
+	return applyOperations(JOURNAL_TARGET_VARIABLE_SET, variableSetId, variables, out, func(variable *PushVariable) (string, error) {
+		switch variable.operation {
+		case PUSH_OPERATION_CREATE:
+			created, err := tfeVariableSetVariables.Create(ctx, variableSetId, &tfe.VariableSetVariableCreateOptions{
+				Key:         variable.createOption.Key,
+				Value:       variable.createOption.Value,
+				Description: variable.createOption.Description,
+				Category:    variable.createOption.Category,
+				HCL:         variable.createOption.HCL,
+				Sensitive:   variable.createOption.Sensitive,
+			})
+			if err != nil || created == nil {
+				return "", err
+			}
+			return created.ID, nil
+		case PUSH_OPERATION_UPDATE:
+			_, err := tfeVariableSetVariables.Update(ctx, variableSetId, variable.id, &tfe.VariableSetVariableUpdateOptions{
+				Key:         variable.updateOption.Key,
+				Value:       variable.updateOption.Value,
+				Description: variable.updateOption.Description,
+				HCL:         variable.updateOption.HCL,
+				Sensitive:   variable.updateOption.Sensitive,
+			})
+			return variable.id, err
+		case PUSH_OPERATION_DELETE:
+			return variable.id, tfeVariableSetVariables.Delete(ctx, variableSetId, variable.id)
+		}
+		return "", fmt.Errorf("unknown operation '%s'", variable.operation)
+	})
+}
+
+// applyOperations call apply for each operation and report failures by key
+// apply returns ID of the variable operated, and applied operations are recorded in change journal
+func applyOperations(target string, targetId string, variables []*PushVariable, out io.Writer, apply func(*PushVariable) (string, error)) error {
+	errs := []error{}
+	counts := map[string]int{}
+	applied := []*JournalOperation{}
+
+	for _, variable := range variables {
+		id, err := apply(variable)
+		if err != nil {
+			log.Error().Err(err).Msgf("failed to %s variable %s", variable.operation, variable.key())
+			fmt.Fprintf(out, "failed to %s %s: %v\n", variable.operation, variable.key(), err)
+			errs = append(errs, fmt.Errorf("failed to %s %s: %w", variable.operation, variable.key(), err))
+			continue
+		}
+		counts[variable.operation]++
+		applied = append(applied, newJournalOperation(variable, id))
 	}
-	log.Info().Msgf("create: %d, update: %d, delete: %d", countCreate, countUpdate, countDelete)
+	log.Info().Msgf("create: %d, update: %d, delete: %d, failed: %d",
+		counts[PUSH_OPERATION_CREATE], counts[PUSH_OPERATION_UPDATE], counts[PUSH_OPERATION_DELETE], len(errs))
 
-	return nil
+	if err := changeJournal.record(target, targetId, applied); err != nil {
+		log.Warn().Err(err).Msg("failed to record changes in journal")
+	}
+
+	return errors.Join(errs...)
+}
+
+// describeMetadataChanges return lines of attribute changes other than value
+func describeMetadataChanges(variables []*PushVariable) string {
+	var buf strings.Builder
+
+	for _, variable := range variables {
+		for _, change := range variable.metadataChanges() {
+			buf.WriteString(formatMetadataChange(variable.key(), change) + "\n")
+		}
+	}
+
+	return buf.String()
 }
 
 func variableEqual(updateOpt tfe.VariableUpdateOptions, targetVariable *tfe.Variable) bool {
 	if *updateOpt.Key != targetVariable.Key ||
-		*updateOpt.Value != targetVariable.Value ||
+		!variableValueEqual(*updateOpt.Value, *updateOpt.HCL, targetVariable) ||
 		*updateOpt.Description != targetVariable.Description ||
 		*updateOpt.Category != targetVariable.Category ||
 		*updateOpt.HCL != targetVariable.HCL ||
@@ -213,15 +500,44 @@ func variableEqual(updateOpt tfe.VariableUpdateOptions, targetVariable *tfe.Vari
 	return true
 }
 
-func confirm(in io.Reader) (bool, error) {
-	r := bufio.NewReader(in)
+// variableValueEqual compare parsed values if HCL, or raw string otherwise
+func variableValueEqual(value string, hcl bool, targetVariable *tfe.Variable) bool {
+	if !hcl || !targetVariable.HCL {
+		return value == targetVariable.Value
+	}
+
+	return CompareValues(CtyValue(targetVariable.Value), CtyValue(value)) == DIFF_UNCHANGED
+}
+
+// alignEquivalentValues return copy of local variables whose values are replaced with remote ones if equivalent,
+// so that only real changes appear in text diff
+func alignEquivalentValues(localVars []*tfe.Variable, remoteVars []*tfe.Variable) []*tfe.Variable {
+	aligned := make([]*tfe.Variable, 0, len(localVars))
+
+	for _, v := range localVars {
+		for _, remote := range remoteVars {
+			if remote.Key == v.Key && !remote.Sensitive && variableValueEqual(v.Value, remote.HCL, remote) {
+				copied := *v
+				copied.Value = remote.Value
+				copied.HCL = remote.HCL
+				v = &copied
+				break
+			}
+		}
+		aligned = append(aligned, v)
+	}
+
+	return aligned
+}
 
-	input, err := r.ReadString('\n')
+// confirm read y/n answer, surrounding spaces and CR of CRLF are ignored
+func confirm(in io.Reader) (bool, error) {
+	input, err := readLine(bufio.NewReader(in))
 	if err != nil {
 		return false, err
 	}
 
-	switch strings.ToLower(strings.TrimRight(input, "\n")) {
+	switch strings.ToLower(input) {
 	case "y", "yes":
 		return true, nil
 	}
@@ -239,6 +239,245 @@ func TestCmdPush(t *testing.T) {
 			wantErr:   false,
 			expectErr: "",
 		},
+		{
+			name:        "delete only selected variables",
+			workspaceId: "w-test-filter-delete-workspace",
+			pushOpt:     &PushOption{delete: true, autoApprove: true, filter: &VariableFilter{includes: []string{"db_*"}}},
+			vars: &tfe.VariableList{
+				Items: []*tfe.Variable{
+					{
+						Key:   "environment",
+						Value: "test",
+					},
+					{
+						Key:   "db_host",
+						Value: "localhost",
+					},
+				},
+			},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-filter-delete-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:    "variable-id-db-host",
+								Key:   "db_host",
+								Value: "localhost",
+							},
+							{
+								ID:    "variable-id-db-port",
+								Key:   "db_port",
+								Value: "5432",
+							},
+							{
+								ID:    "variable-id-region",
+								Key:   "region",
+								Value: "ap-northeast-1",
+							},
+						},
+					}, nil).
+					AnyTimes()
+				mc.EXPECT().
+					Create(context.TODO(), "w-test-filter-delete-workspace", gomock.Any()).
+					Times(0)
+				mc.EXPECT().
+					Delete(context.TODO(), "w-test-filter-delete-workspace", "variable-id-db-port").
+					Return(nil).
+					Times(1)
+			},
+		},
+		{
+			name:        "sync descriptions from local comments",
+			workspaceId: "w-test-sync-descriptions",
+			pushOpt:     &PushOption{autoApprove: true, syncDescriptions: true},
+			vars: &tfe.VariableList{
+				Items: []*tfe.Variable{
+					{
+						Key:         "environment",
+						Value:       "test",
+						Description: "Environment name",
+					},
+					{
+						Key:         "region",
+						Value:       "ap-northeast-1",
+						Description: "AWS region",
+					},
+				},
+			},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-sync-descriptions", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:       "variable-id-environment",
+								Key:      "environment",
+								Value:    "test",
+								Category: tfe.CategoryTerraform,
+							},
+						},
+					}, nil).
+					AnyTimes()
+				mc.EXPECT().
+					Update(context.TODO(), "w-test-sync-descriptions", "variable-id-environment", tfe.VariableUpdateOptions{
+						Key:         tfe.String("environment"),
+						Value:       tfe.String("test"),
+						Description: tfe.String("Environment name"),
+						Category:    tfe.Category(tfe.CategoryTerraform),
+						HCL:         tfe.Bool(false),
+						Sensitive:   tfe.Bool(false),
+					}).
+					Return(&tfe.Variable{}, nil).
+					Times(1)
+				mc.EXPECT().
+					Create(context.TODO(), "w-test-sync-descriptions", tfe.VariableCreateOptions{
+						Key:         tfe.String("region"),
+						Value:       tfe.String("ap-northeast-1"),
+						Description: tfe.String("AWS region"),
+						Category:    tfe.Category(tfe.CategoryTerraform),
+						HCL:         tfe.Bool(false),
+						Sensitive:   tfe.Bool(false),
+					}).
+					Return(&tfe.Variable{}, nil).
+					Times(1)
+			},
+		},
+		{
+			name:        "keep remote description of variable without comment",
+			workspaceId: "w-test-sync-descriptions-without-comment",
+			pushOpt:     &PushOption{autoApprove: true, syncDescriptions: true},
+			vars: &tfe.VariableList{
+				Items: []*tfe.Variable{
+					{
+						Key:         "environment",
+						Value:       "test",
+						Description: "Environment name",
+					},
+					{
+						Key:   "region",
+						Value: "us-east-1",
+					},
+				},
+			},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-sync-descriptions-without-comment", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:          "variable-id-environment",
+								Key:         "environment",
+								Value:       "test",
+								Description: "old description",
+								Category:    tfe.CategoryTerraform,
+							},
+							{
+								ID:          "variable-id-region",
+								Key:         "region",
+								Value:       "ap-northeast-1",
+								Description: "AWS region",
+								Category:    tfe.CategoryTerraform,
+							},
+						},
+					}, nil).
+					AnyTimes()
+				mc.EXPECT().
+					Update(context.TODO(), "w-test-sync-descriptions-without-comment", "variable-id-environment", tfe.VariableUpdateOptions{
+						Key:         tfe.String("environment"),
+						Value:       tfe.String("test"),
+						Description: tfe.String("Environment name"),
+						Category:    tfe.Category(tfe.CategoryTerraform),
+						HCL:         tfe.Bool(false),
+						Sensitive:   tfe.Bool(false),
+					}).
+					Return(&tfe.Variable{}, nil).
+					Times(1)
+				mc.EXPECT().
+					Update(context.TODO(), "w-test-sync-descriptions-without-comment", "variable-id-region", tfe.VariableUpdateOptions{
+						Key:         tfe.String("region"),
+						Value:       tfe.String("us-east-1"),
+						Description: tfe.String("AWS region"),
+						Category:    tfe.Category(tfe.CategoryTerraform),
+						HCL:         tfe.Bool(false),
+						Sensitive:   tfe.Bool(false),
+					}).
+					Return(&tfe.Variable{}, nil).
+					Times(1)
+			},
+		},
+		{
+			name:        "not update variable with equivalent hcl value",
+			workspaceId: "w-test-equivalent-hcl-value",
+			pushOpt:     &PushOption{},
+			vars: &tfe.VariableList{
+				Items: []*tfe.Variable{
+					{
+						Key:   "tags",
+						Value: `{a = "1", b = "2"}`,
+						HCL:   true,
+					},
+				},
+			},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-equivalent-hcl-value", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:       "variable-id-tags",
+								Key:      "tags",
+								Value:    "{\n  b = \"2\"\n  a = \"1\"\n}",
+								HCL:      true,
+								Category: tfe.CategoryTerraform,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+		},
+		{
+			name:        "show description only change before confirm",
+			workspaceId: "w-test-confirm-description-change",
+			pushOpt:     &PushOption{syncDescriptions: true},
+			vars: &tfe.VariableList{
+				Items: []*tfe.Variable{
+					{
+						Key:         "environment",
+						Value:       "test",
+						Description: "Environment name",
+					},
+				},
+			},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-confirm-description-change", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:       "variable-id-environment",
+								Key:      "environment",
+								Value:    "test",
+								Category: tfe.CategoryTerraform,
+							},
+						},
+					}, nil).
+					AnyTimes()
+				mc.EXPECT().
+					Update(context.TODO(), "w-test-confirm-description-change", "variable-id-environment", tfe.VariableUpdateOptions{
+						Key:         tfe.String("environment"),
+						Value:       tfe.String("test"),
+						Description: tfe.String("Environment name"),
+						Category:    tfe.Category(tfe.CategoryTerraform),
+						HCL:         tfe.Bool(false),
+						Sensitive:   tfe.Bool(false),
+					}).
+					Return(&tfe.Variable{}, nil).
+					Times(1)
+			},
+			input:  "y\n",
+			expect: "~ environment: description \"\" -> \"Environment name\"\n",
+		},
 		{
 			name:        "require confirm and update variable after confirmed",
 			workspaceId: "w-test-require-confirm-variable",
@@ -337,7 +576,152 @@ func TestCmdPush(t *testing.T) {
 			},
 			input:     "",
 			wantErr:   true,
-			expectErr: "EOF",
+			expectErr: "no input for confirmation",
+		},
+		{
+			name:        "interactive push applies only accepted changes",
+			workspaceId: "w-test-interactive",
+			pushOpt:     &PushOption{interactive: true},
+			vars: &tfe.VariableList{
+				Items: []*tfe.Variable{
+					{
+						Key:   "environment",
+						Value: "test",
+					},
+					{
+						Key:   "port",
+						Value: "8080",
+					},
+				},
+			},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-interactive", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:    "variable-id-environment",
+								Key:   "environment",
+								Value: "development",
+							},
+						},
+					}, nil).
+					AnyTimes()
+				mc.EXPECT().
+					Update(context.TODO(), "w-test-interactive", "variable-id-environment", gomock.Any()).
+					Times(0)
+				mc.EXPECT().
+					Create(context.TODO(), "w-test-interactive", tfe.VariableCreateOptions{
+						Key:       tfe.String("port"),
+						Value:     tfe.String("9090"),
+						Category:  tfe.Category(tfe.CategoryTerraform),
+						HCL:       tfe.Bool(false),
+						Sensitive: tfe.Bool(false),
+					}).
+					Return(&tfe.Variable{}, nil).
+					Times(1)
+			},
+			input: "s\ne\n9090\na\ny\n",
+		},
+		{
+			name:        "interactive push does nothing if quit",
+			workspaceId: "w-test-interactive-quit",
+			pushOpt:     &PushOption{interactive: true},
+			vars: &tfe.VariableList{
+				Items: []*tfe.Variable{
+					{
+						Key:   "environment",
+						Value: "test",
+					},
+				},
+			},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-interactive-quit", nil).
+					Return(&tfe.VariableList{}, nil).
+					AnyTimes()
+			},
+			input: "q\n",
+		},
+		{
+			name:        "mask local value of remote sensitive variable in confirmation",
+			workspaceId: "w-test-mask-sensitive",
+			pushOpt:     &PushOption{interactive: true},
+			vars: &tfe.VariableList{
+				Items: []*tfe.Variable{
+					{
+						Key:   "db_password",
+						Value: "supersecret",
+					},
+				},
+			},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-mask-sensitive", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:        "variable-id-db-password",
+								Key:       "db_password",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			input:  "s\n",
+			expect: "\n[1/1] update db_password: \"(sensitive)\" -> \"(sensitive)\"\nApply this change? [a]ccept, [s]kip, [e]dit, [q]uit: \nPlan: 0 to create, 0 to update, 0 to delete, 1 skipped.\n",
+		},
+		{
+			name:        "mask local value of remote sensitive variable in diff",
+			workspaceId: "w-test-mask-sensitive-diff",
+			pushOpt:     &PushOption{},
+			vars: &tfe.VariableList{
+				Items: []*tfe.Variable{
+					{
+						Key:   "db_password",
+						Value: "supersecret",
+					},
+				},
+			},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-mask-sensitive-diff", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:        "variable-id-db-password",
+								Key:       "db_password",
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			input:  "no\n",
+			expect: "- // db_password = \"***\"\n+ db_password = \"(sensitive)\"\n",
+		},
+		{
+			name:        "warn plaintext value of variable sensitive in variable set",
+			workspaceId: "w-test-varset-sensitive",
+			pushOpt:     &PushOption{autoApprove: true, remoteSensitive: map[string]bool{"db_password": true}},
+			vars: &tfe.VariableList{
+				Items: []*tfe.Variable{
+					{
+						Key:   "db_password",
+						Value: "supersecret",
+					},
+				},
+			},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-varset-sensitive", nil).
+					Return(&tfe.VariableList{}, nil)
+				mc.EXPECT().
+					Create(context.TODO(), "w-test-varset-sensitive", gomock.Any()).
+					Return(&tfe.Variable{ID: "variable-id-db-password"}, nil)
+			},
+			expect: "Warning: 'db_password' is sensitive in Terraform Cloud but will be uploaded as non-sensitive\n",
 		},
 		{
 			name:        "return error if failed to access terraform cloud",
@@ -378,6 +762,9 @@ func TestCmdPush(t *testing.T) {
 			if tt.expect != "" && !bytes.Equal(outBuf.Bytes(), []byte(tt.expect)) {
 				t.Errorf("expect '%s', got '%s'", tt.expect, outBuf.Bytes())
 			}
+			if strings.Contains(outBuf.String(), "supersecret") {
+				t.Errorf("expect sensitive value not to be written, got '%s'", outBuf.String())
+			}
 		})
 	}
 }
@@ -410,22 +797,33 @@ func TestNewPushOption(t *testing.T) {
 			name: "variable option",
 			args: []string{"--variable", "key=value"},
 			expect: &PushOption{
-				varFile:       "terraform.tfvars",
-				variableKey:   "key",
-				variableValue: "value",
-				in:            os.Stdin,
-				out:           os.Stdout,
+				varFile:   "terraform.tfvars",
+				variables: []*tfe.Variable{{Key: "key", Value: "value"}},
+				filter:    &VariableFilter{keys: []string{"key"}},
+				in:        os.Stdin,
+				out:       os.Stdout,
 			},
 		},
 		{
 			name: "variable option with include equal",
 			args: []string{"--variable", "key=value=10"},
 			expect: &PushOption{
-				varFile:       "terraform.tfvars",
-				variableKey:   "key",
-				variableValue: "value=10",
-				in:            os.Stdin,
-				out:           os.Stdout,
+				varFile:   "terraform.tfvars",
+				variables: []*tfe.Variable{{Key: "key", Value: "value=10"}},
+				filter:    &VariableFilter{keys: []string{"key"}},
+				in:        os.Stdin,
+				out:       os.Stdout,
+			},
+		},
+		{
+			name: "select multiple variables",
+			args: []string{"--variable", "environment", "--variable", "port=8080"},
+			expect: &PushOption{
+				varFile:   "terraform.tfvars",
+				variables: []*tfe.Variable{{Key: "port", Value: "8080"}},
+				filter:    &VariableFilter{keys: []string{"environment", "port"}},
+				in:        os.Stdin,
+				out:       os.Stdout,
 			},
 		},
 		{
@@ -448,6 +846,26 @@ func TestNewPushOption(t *testing.T) {
 				out:         os.Stdout,
 			},
 		},
+		{
+			name: "sync descriptions option enabled",
+			args: []string{"--sync-descriptions"},
+			expect: &PushOption{
+				varFile:          "terraform.tfvars",
+				syncDescriptions: true,
+				in:               os.Stdin,
+				out:              os.Stdout,
+			},
+		},
+		{
+			name: "interactive option enabled",
+			args: []string{"--interactive"},
+			expect: &PushOption{
+				varFile:     "terraform.tfvars",
+				interactive: true,
+				in:          os.Stdin,
+				out:         os.Stdout,
+			},
+		},
 	}
 
 	for _, tt := range cases {
@@ -466,6 +884,105 @@ func TestNewPushOption(t *testing.T) {
 	}
 }
 
+func TestPushOptionValidate(t *testing.T) {
+	cases := []struct {
+		name      string
+		pushOpt   *PushOption
+		expectErr string
+	}{
+		{
+			name:    "no filter",
+			pushOpt: &PushOption{},
+		},
+		{
+			name:    "terraform category",
+			pushOpt: &PushOption{filter: &VariableFilter{category: string(tfe.CategoryTerraform)}},
+		},
+		{
+			name:      "env category",
+			pushOpt:   &PushOption{filter: &VariableFilter{category: string(tfe.CategoryEnv)}},
+			expectErr: "--category env is not supported by push command",
+		},
+		{
+			name:      "invalid pattern",
+			pushOpt:   &PushOption{filter: &VariableFilter{includes: []string{"["}}},
+			expectErr: "invalid pattern",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pushOpt.Validate()
+
+			if tt.expectErr == "" {
+				if err != nil {
+					t.Errorf("expect no error, got error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("expect '%s' error, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestPushOptionLocalVariables(t *testing.T) {
+	cases := []struct {
+		name    string
+		pushOpt *PushOption
+		expect  []*tfe.Variable
+	}{
+		{
+			name:    "read var-file",
+			pushOpt: &PushOption{varFile: "testdata/terraform.tfvars"},
+			expect: []*tfe.Variable{
+				{Key: "environment", Value: "development"},
+			},
+		},
+		{
+			name: "use specified values without var-file",
+			pushOpt: &PushOption{
+				varFile:   "testdata/notfound.tfvars",
+				variables: []*tfe.Variable{{Key: "port", Value: "8080"}},
+				filter:    &VariableFilter{keys: []string{"port"}},
+			},
+			expect: []*tfe.Variable{
+				{Key: "port", Value: "8080"},
+			},
+		},
+		{
+			name: "override var-file with specified values",
+			pushOpt: &PushOption{
+				varFile:   "testdata/terraform.tfvars",
+				variables: []*tfe.Variable{{Key: "environment", Value: "production"}},
+				filter:    &VariableFilter{keys: []string{"environment", "port"}},
+			},
+			expect: []*tfe.Variable{
+				{Key: "environment", Value: "production"},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.pushOpt.localVariables()
+			if err != nil {
+				t.Fatalf("expect no error, got error: %v", err)
+			}
+
+			if len(actual) != len(tt.expect) {
+				t.Fatalf("expect %d variables, got %d", len(tt.expect), len(actual))
+			}
+			for i := range tt.expect {
+				if actual[i].Key != tt.expect[i].Key || actual[i].Value != tt.expect[i].Value {
+					t.Errorf("expect %s = %s, got %s = %s", tt.expect[i].Key, tt.expect[i].Value, actual[i].Key, actual[i].Value)
+				}
+			}
+		})
+	}
+}
+
 func TestVariableEqual(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -601,17 +1118,24 @@ func TestConfirm(t *testing.T) {
 			expectErr:  "",
 			wantErr:    false,
 		},
+		{
+			name:       "return true from y with CRLF",
+			input:      "y\r\n",
+			expectBool: true,
+			expectErr:  "",
+			wantErr:    false,
+		},
 		{
 			name:       "return error without newline",
 			input:      "yes",
 			expectBool: false,
-			expectErr:  "EOF",
+			expectErr:  "no input for confirmation: use --auto-approve",
 			wantErr:    true,
 		},
 		{
 			name:       "return error if no input specified",
 			expectBool: false,
-			expectErr:  "EOF",
+			expectErr:  "no input for confirmation: use --auto-approve",
 			wantErr:    true,
 		},
 	}
@@ -626,7 +1150,7 @@ func TestConfirm(t *testing.T) {
 			} else if !strings.Contains(err.Error(), tt.expectErr) {
 				t.Errorf("expect error '%s', got '%v'", tt.expectErr, err)
 			}
-			return
+			continue
 		}
 		if err != nil {
 			t.Errorf("expect no error, got error '%v'", err)
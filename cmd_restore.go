@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+type RestoreOption struct {
+	dir                string
+	organization       string
+	workspaces         []string
+	includeVariableSet bool
+	delete             bool
+	dryRun             bool
+	autoApprove        bool
+	reveal             bool
+	in                 io.Reader
+	out                io.Writer
+}
+
+func NewRestoreOption(c *cli.Context) *RestoreOption {
+	var opt = &RestoreOption{}
+
+	opt.dir = c.Args().First()
+	opt.organization = c.String("org")
+	opt.workspaces = c.StringSlice("workspace")
+	opt.includeVariableSet = c.Bool("include-variable-set")
+	opt.delete = c.Bool("delete")
+	opt.dryRun = c.Bool("dry-run")
+	opt.autoApprove = c.Bool("auto-approve")
+	opt.reveal = c.Bool("reveal")
+
+	opt.in = os.Stdin
+	opt.out = os.Stdout
+
+	return opt
+}
+
+// restoreTarget is planned operations to restore a workspace or variable set
+// err is set if operations cannot be planned, such as the workspace does not exist
+type restoreTarget struct {
+	kind  string
+	name  string
+	plan  *copyPlan
+	apply func([]*PushVariable) error
+	err   error
+}
+
+func Restore(c *cli.Context) error {
+	ctx := context.Background()
+	log.Debug().Msg("restore command")
+
+	restoreOpt := NewRestoreOption(c)
+	if restoreOpt.dir == "" {
+		return errors.New("specify backup directory written by export command")
+	}
+	manifest, err := readBackupManifest(restoreOpt.dir)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to read backup in %s", restoreOpt.dir)
+		return err
+	}
+	if restoreOpt.organization == "" {
+		restoreOpt.organization = manifest.Organization
+	}
+
+	tfeClient, err := NewTfeClient(c)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build tfe client")
+		return err
+	}
+
+	targets := []*restoreTarget{}
+	for _, entry := range manifest.Workspaces {
+		if len(restoreOpt.workspaces) != 0 && !slices.Contains(restoreOpt.workspaces, entry.Name) {
+			continue
+		}
+		target, err := planRestoreWorkspace(ctx, entry, tfeClient.Workspaces, tfeClient.Variables, restoreOpt)
+		if err != nil {
+			target = &restoreTarget{kind: "workspace", name: entry.Name, err: err}
+		}
+		targets = append(targets, target)
+	}
+	if restoreOpt.includeVariableSet {
+		for _, entry := range manifest.VariableSets {
+			target, err := planRestoreVariableSet(ctx, entry, tfeClient.VariableSets, tfeClient.VariableSetVariables, restoreOpt)
+			if err != nil {
+				target = &restoreTarget{kind: "variable set", name: entry.Name, err: err}
+			}
+			targets = append(targets, target)
+		}
+	}
+
+	return applyRestore(targets, restoreOpt)
+}
+
+// planRestoreWorkspace plan operations to restore variables of workspace in backup
+// the workspace must exist, so deleted one has to be created before restore
+// missing workspace is reported as error so that other workspaces are still restored
+func planRestoreWorkspace(ctx context.Context, entry *BackupEntry, tfeWorkspaces tfe.Workspaces, tfeVariables tfe.Variables, restoreOpt *RestoreOption) (*restoreTarget, error) {
+	backupVars, err := readBackupVariables(restoreOpt.dir, entry)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to read backup of workspace %s", entry.Name)
+		return nil, err
+	}
+
+	workspace, err := tfeWorkspaces.Read(ctx, restoreOpt.organization, entry.Name)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to access workspace %s/%s", restoreOpt.organization, entry.Name)
+		if errors.Is(err, tfe.ErrResourceNotFound) {
+			return nil, fmt.Errorf("not found in organization %s, create it and restore again: %w", restoreOpt.organization, err)
+		}
+		return nil, err
+	}
+	variables, err := tfeVariables.List(ctx, workspace.ID, nil)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to list variables of workspace %s", entry.Name)
+		return nil, err
+	}
+
+	return &restoreTarget{
+		kind: "workspace",
+		name: entry.Name,
+		plan: planRestore(backupVars, variables.Items, restoreOpt.delete),
+		apply: func(planned []*PushVariable) error {
+			return applyPushVariables(ctx, workspace.ID, tfeVariables, planned, restoreOpt.out)
+		},
+	}, nil
+}
+
+// planRestoreVariableSet plan operations to restore variables of variable set in backup
+// variable set is looked up by name since ID changes when it is created again
+func planRestoreVariableSet(ctx context.Context, entry *BackupEntry, tfeVariableSets tfe.VariableSets, tfeVariableSetVariables tfe.VariableSetVariables, restoreOpt *RestoreOption) (*restoreTarget, error) {
+	backupVars, err := readBackupVariables(restoreOpt.dir, entry)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to read backup of variable set %s", entry.Name)
+		return nil, err
+	}
+
+	variableSet, err := findVariableSet(ctx, restoreOpt.organization, entry.Name, tfeVariableSets)
+	if err != nil {
+		return nil, err
+	}
+	variableList, err := tfeVariableSetVariables.List(ctx, variableSet.ID, nil)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to list VariableSetVariables ID: %s", variableSet.ID)
+		return nil, err
+	}
+	variables := []*tfe.Variable{}
+	for _, v := range variableList.Items {
+		variables = append(variables, convertVariableSetVariable(v))
+	}
+
+	return &restoreTarget{
+		kind: "variable set",
+		name: entry.Name,
+		plan: planRestore(backupVars, variables, restoreOpt.delete),
+		apply: func(planned []*PushVariable) error {
+			return applyVariableSetPushVariables(ctx, variableSet.ID, tfeVariableSetVariables, planned, restoreOpt.out)
+		},
+	}, nil
+}
+
+// planRestore return operations to make current variables the same as backup
+// values of sensitive variables are not in backup, so existing ones are skipped and missing ones are created with placeholder
+func planRestore(backupVars []*tfe.Variable, currentVars []*tfe.Variable, delete bool) *copyPlan {
+	plan := &copyPlan{
+		variables:   []*PushVariable{},
+		skipped:     []string{},
+		placeholder: []string{},
+	}
+
+	for _, backup := range backupVars {
+		current := findVariable(currentVars, backup)
+		if current == nil {
+			value := backup.Value
+			if backup.Sensitive {
+				value = copyPlaceholder(backup)
+				plan.placeholder = append(plan.placeholder, backup.Key)
+			}
+			plan.variables = append(plan.variables, &PushVariable{
+				operation: PUSH_OPERATION_CREATE,
+				createOption: tfe.VariableCreateOptions{
+					Key:         tfe.String(backup.Key),
+					Value:       tfe.String(value),
+					Description: tfe.String(backup.Description),
+					Category:    tfe.Category(variableCategory(backup)),
+					HCL:         tfe.Bool(backup.HCL),
+					Sensitive:   tfe.Bool(backup.Sensitive),
+				},
+			})
+			continue
+		}
+		if backup.Sensitive {
+			plan.skipped = append(plan.skipped, backup.Key+": already exists")
+			continue
+		}
+
+		updateOption := tfe.VariableUpdateOptions{
+			Key:         tfe.String(backup.Key),
+			Value:       tfe.String(backup.Value),
+			Description: tfe.String(backup.Description),
+			Category:    tfe.Category(variableCategory(backup)),
+			HCL:         tfe.Bool(backup.HCL),
+			Sensitive:   tfe.Bool(backup.Sensitive),
+		}
+		if variableEqual(updateOption, current) {
+			continue
+		}
+		plan.variables = append(plan.variables, &PushVariable{
+			operation:    PUSH_OPERATION_UPDATE,
+			id:           current.ID,
+			previous:     current,
+			updateOption: updateOption,
+		})
+	}
+
+	if delete {
+		for _, current := range currentVars {
+			if findVariable(backupVars, current) == nil {
+				plan.variables = append(plan.variables, &PushVariable{
+					operation: PUSH_OPERATION_DELETE,
+					id:        current.ID,
+					previous:  current,
+				})
+			}
+		}
+	}
+
+	return plan
+}
+
+// findVariable return variable with the same key and category as v
+func findVariable(variables []*tfe.Variable, v *tfe.Variable) *tfe.Variable {
+	for _, variable := range variables {
+		if variable.Key == v.Key && variableCategory(variable) == variableCategory(v) {
+			return variable
+		}
+	}
+
+	return nil
+}
+
+// applyRestore print plans of all targets, confirm once and apply them
+// restore continues on failure of a target and errors of all targets are returned
+// targets failed to plan are skipped and reported as errors as well
+func applyRestore(targets []*restoreTarget, restoreOpt *RestoreOption) error {
+	errs := []error{}
+	changes := 0
+	for _, target := range targets {
+		fmt.Fprintf(restoreOpt.out, "%s %s:\n", target.kind, target.name)
+		if target.err != nil {
+			fmt.Fprintf(restoreOpt.out, "skip: %s\n", target.err)
+			errs = append(errs, fmt.Errorf("%s %s: %w", target.kind, target.name, target.err))
+			continue
+		}
+		target.plan.write(restoreOpt.out, restoreOpt.reveal)
+		changes += len(target.plan.variables)
+	}
+	if changes == 0 || restoreOpt.dryRun {
+		return errors.Join(errs...)
+	}
+
+	if !restoreOpt.autoApprove {
+		if err := requireTerminal(restoreOpt.in); err != nil {
+			return err
+		}
+		fmt.Fprint(restoreOpt.out, "\nAre you sure you want to restore variables in Terraform Cloud? [y/n]: ")
+		res, err := confirm(restoreOpt.in)
+		if err != nil {
+			return err
+		}
+		if !res {
+			return errors.Join(errs...)
+		}
+	}
+
+	for _, target := range targets {
+		if target.err != nil || len(target.plan.variables) == 0 {
+			continue
+		}
+		if err := target.apply(target.plan.variables); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", target.kind, target.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-tfe/mocks"
+	"github.com/urfave/cli/v2"
+)
+
+func TestPlanRestore(t *testing.T) {
+	backupVars := []*tfe.Variable{
+		{ID: "v-environment", Key: "environment", Value: "production", Description: "env name", Category: tfe.CategoryTerraform},
+		{ID: "v-zones", Key: "zones", Value: `["a", "b"]`, Category: tfe.CategoryTerraform, HCL: true},
+		{ID: "v-db_password", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+	}
+
+	cases := []struct {
+		name        string
+		currentVars []*tfe.Variable
+		delete      bool
+		expect      string
+	}{
+		{
+			name:        "create all variables of deleted workspace",
+			currentVars: []*tfe.Variable{},
+			expect: `create environment = "production"
+create zones = "[\"a\", \"b\"]"
+create db_password = "(sensitive)"
+Plan: 3 to create, 0 to update, 0 to delete, 0 skipped.
+
+Sensitive variables are created with placeholder values, set their values afterwards:
+  db_password
+`,
+		},
+		{
+			name: "print no changes if variables are the same",
+			currentVars: []*tfe.Variable{
+				{ID: "v-environment", Key: "environment", Value: "production", Description: "env name", Category: tfe.CategoryTerraform},
+				{ID: "v-zones", Key: "zones", Value: `["a","b"]`, Category: tfe.CategoryTerraform, HCL: true},
+				{ID: "v-extra", Key: "extra", Value: "value", Category: tfe.CategoryTerraform},
+			},
+			expect: `create db_password = "(sensitive)"
+Plan: 1 to create, 0 to update, 0 to delete, 0 skipped.
+
+Sensitive variables are created with placeholder values, set their values afterwards:
+  db_password
+`,
+		},
+		{
+			name: "update changed variables, skip sensitive and delete extra",
+			currentVars: []*tfe.Variable{
+				{ID: "v-environment", Key: "environment", Value: "staging", Description: "env name", Category: tfe.CategoryTerraform},
+				{ID: "v-zones", Key: "zones", Value: `["a", "b"]`, Category: tfe.CategoryTerraform, HCL: true},
+				{ID: "v-db_password", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+				{ID: "v-extra", Key: "extra", Value: "value", Category: tfe.CategoryTerraform},
+			},
+			delete: true,
+			expect: `update environment: "staging" -> "production"
+delete extra
+skip db_password: already exists
+Plan: 0 to create, 1 to update, 1 to delete, 1 skipped.
+`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planRestore(backupVars, tt.currentVars, tt.delete)
+
+			outBuf := new(bytes.Buffer)
+			plan.write(outBuf, false)
+			if outBuf.String() != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, outBuf.String())
+			}
+		})
+	}
+}
+
+func TestRestoreWorkspace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockWorkspaces := mocks.NewMockWorkspaces(ctrl)
+	mockVariables := mocks.NewMockVariables(ctrl)
+
+	dir := t.TempDir()
+	entry := &BackupEntry{ID: "ws-old", Name: "production", File: "workspaces/production.json", Variables: 1}
+	if err := writeBackupFile(dir, entry.File, newVariableOutputs([]*tfe.Variable{
+		{ID: "v-environment", Key: "environment", Value: "production", Category: tfe.CategoryTerraform},
+	}, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name       string
+		restoreOpt *RestoreOption
+		setClient  func(*mocks.MockWorkspaces, *mocks.MockVariables)
+		input      string
+		expect     string
+		wantErr    bool
+		expectErr  string
+	}{
+		{
+			name:       "restore variables into recreated workspace",
+			restoreOpt: &RestoreOption{autoApprove: true},
+			setClient: func(mw *mocks.MockWorkspaces, mv *mocks.MockVariables) {
+				mw.EXPECT().Read(gomock.Any(), "org", "production").Return(&tfe.Workspace{ID: "ws-new"}, nil)
+				mv.EXPECT().List(gomock.Any(), "ws-new", nil).Return(&tfe.VariableList{Items: []*tfe.Variable{}}, nil)
+				mv.EXPECT().Create(gomock.Any(), "ws-new", tfe.VariableCreateOptions{
+					Key:         tfe.String("environment"),
+					Value:       tfe.String("production"),
+					Description: tfe.String(""),
+					Category:    tfe.Category(tfe.CategoryTerraform),
+					HCL:         tfe.Bool(false),
+					Sensitive:   tfe.Bool(false),
+				}).Return(&tfe.Variable{}, nil)
+			},
+			expect: "workspace production:\ncreate environment = \"production\"\nPlan: 1 to create, 0 to update, 0 to delete, 0 skipped.\n",
+		},
+		{
+			name:       "print plan without restoring in dry-run mode",
+			restoreOpt: &RestoreOption{dryRun: true},
+			setClient: func(mw *mocks.MockWorkspaces, mv *mocks.MockVariables) {
+				mw.EXPECT().Read(gomock.Any(), "org", "production").Return(&tfe.Workspace{ID: "ws-new"}, nil)
+				mv.EXPECT().List(gomock.Any(), "ws-new", nil).Return(&tfe.VariableList{Items: []*tfe.Variable{}}, nil)
+			},
+			expect: "workspace production:\ncreate environment = \"production\"\nPlan: 1 to create, 0 to update, 0 to delete, 0 skipped.\n",
+		},
+		{
+			name:       "do nothing if approve declined",
+			restoreOpt: &RestoreOption{},
+			setClient: func(mw *mocks.MockWorkspaces, mv *mocks.MockVariables) {
+				mw.EXPECT().Read(gomock.Any(), "org", "production").Return(&tfe.Workspace{ID: "ws-new"}, nil)
+				mv.EXPECT().List(gomock.Any(), "ws-new", nil).Return(&tfe.VariableList{Items: []*tfe.Variable{}}, nil)
+			},
+			input:  "n\n",
+			expect: "workspace production:\ncreate environment = \"production\"\nPlan: 1 to create, 0 to update, 0 to delete, 0 skipped.\n\nAre you sure you want to restore variables in Terraform Cloud? [y/n]: ",
+		},
+		{
+			name:       "report failure of workspace",
+			restoreOpt: &RestoreOption{autoApprove: true},
+			setClient: func(mw *mocks.MockWorkspaces, mv *mocks.MockVariables) {
+				mw.EXPECT().Read(gomock.Any(), "org", "production").Return(&tfe.Workspace{ID: "ws-new"}, nil)
+				mv.EXPECT().List(gomock.Any(), "ws-new", nil).Return(&tfe.VariableList{Items: []*tfe.Variable{}}, nil)
+				mv.EXPECT().Create(gomock.Any(), "ws-new", gomock.Any()).Return(nil, errors.New("permission denied"))
+			},
+			wantErr:   true,
+			expectErr: "workspace production: failed to create environment: permission denied",
+		},
+		{
+			name:       "fail if workspace does not exist",
+			restoreOpt: &RestoreOption{autoApprove: true},
+			setClient: func(mw *mocks.MockWorkspaces, mv *mocks.MockVariables) {
+				mw.EXPECT().Read(gomock.Any(), "org", "production").Return(nil, tfe.ErrResourceNotFound)
+			},
+			wantErr:   true,
+			expectErr: "not found in organization org, create it and restore again",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			tt.setClient(mockWorkspaces, mockVariables)
+			tt.restoreOpt.dir = dir
+			tt.restoreOpt.organization = "org"
+			tt.restoreOpt.in = strings.NewReader(tt.input)
+			outBuf := new(bytes.Buffer)
+			tt.restoreOpt.out = outBuf
+
+			target, err := planRestoreWorkspace(ctx, entry, mockWorkspaces, mockVariables, tt.restoreOpt)
+			if err == nil {
+				err = applyRestore([]*restoreTarget{target}, tt.restoreOpt)
+			}
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expect '%s' error, got no error", tt.expectErr)
+				} else if !strings.Contains(err.Error(), tt.expectErr) {
+					t.Errorf("expect '%s' error, got '%s'", tt.expectErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+			if outBuf.String() != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, outBuf.String())
+			}
+		})
+	}
+}
+
+func TestApplyRestoreSkipFailedTarget(t *testing.T) {
+	applied := []string{}
+	targets := []*restoreTarget{
+		{kind: "workspace", name: "staging", err: errors.New("not found in organization org, create it and restore again")},
+		{
+			kind: "workspace",
+			name: "production",
+			plan: &copyPlan{
+				variables: []*PushVariable{{
+					operation:    PUSH_OPERATION_CREATE,
+					createOption: tfe.VariableCreateOptions{Key: tfe.String("environment"), Value: tfe.String("production")},
+				}},
+			},
+			apply: func(planned []*PushVariable) error {
+				for _, variable := range planned {
+					applied = append(applied, variable.key())
+				}
+				return nil
+			},
+		},
+	}
+	outBuf := new(bytes.Buffer)
+	restoreOpt := &RestoreOption{autoApprove: true, out: outBuf}
+
+	err := applyRestore(targets, restoreOpt)
+
+	expectErr := "workspace staging: not found in organization org, create it and restore again"
+	if err == nil || err.Error() != expectErr {
+		t.Errorf("expect '%s' error, got '%v'", expectErr, err)
+	}
+	if !reflect.DeepEqual(applied, []string{"environment"}) {
+		t.Errorf("expect other workspace restored, got '%v'", applied)
+	}
+	expect := "workspace staging:\nskip: not found in organization org, create it and restore again\nworkspace production:\ncreate environment = \"production\"\nPlan: 1 to create, 0 to update, 0 to delete, 0 skipped.\n"
+	if outBuf.String() != expect {
+		t.Errorf("expect '%s', got '%s'", expect, outBuf.String())
+	}
+}
+
+func TestRestoreVariableSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVariableSets := mocks.NewMockVariableSets(ctrl)
+	mockVariableSetVariables := mocks.NewMockVariableSetVariables(ctrl)
+
+	dir := t.TempDir()
+	entry := &BackupEntry{ID: "varset-old", Name: "aws", File: "variable-sets/varset-old.json", Variables: 1}
+	if err := writeBackupFile(dir, entry.File, newVariableOutputs([]*tfe.Variable{
+		{ID: "var-region", Key: "AWS_REGION", Value: "ap-northeast-1", Category: tfe.CategoryEnv},
+	}, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	mockVariableSets.EXPECT().List(gomock.Any(), "org", gomock.Any()).Return(&tfe.VariableSetList{
+		Items: []*tfe.VariableSet{{ID: "varset-new", Name: "aws"}},
+	}, nil)
+	mockVariableSetVariables.EXPECT().List(gomock.Any(), "varset-new", nil).Return(&tfe.VariableSetVariableList{
+		Items: []*tfe.VariableSetVariable{
+			{ID: "var-new-region", Key: "AWS_REGION", Value: "us-east-1", Category: tfe.CategoryEnv},
+		},
+	}, nil)
+	mockVariableSetVariables.EXPECT().Update(gomock.Any(), "varset-new", "var-new-region", &tfe.VariableSetVariableUpdateOptions{
+		Key:         tfe.String("AWS_REGION"),
+		Value:       tfe.String("ap-northeast-1"),
+		Description: tfe.String(""),
+		HCL:         tfe.Bool(false),
+		Sensitive:   tfe.Bool(false),
+	}).Return(&tfe.VariableSetVariable{}, nil)
+
+	outBuf := new(bytes.Buffer)
+	restoreOpt := &RestoreOption{dir: dir, organization: "org", autoApprove: true, out: outBuf}
+	target, err := planRestoreVariableSet(context.TODO(), entry, mockVariableSets, mockVariableSetVariables, restoreOpt)
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	if err := applyRestore([]*restoreTarget{target}, restoreOpt); err != nil {
+		t.Errorf("expect no error, got error: %v", err)
+	}
+
+	expect := "variable set aws:\nupdate AWS_REGION: \"us-east-1\" -> \"ap-northeast-1\"\nPlan: 0 to create, 1 to update, 0 to delete, 0 skipped.\n"
+	if outBuf.String() != expect {
+		t.Errorf("expect '%s', got '%s'", expect, outBuf.String())
+	}
+}
+
+func TestNewRestoreOption(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		expect *RestoreOption
+	}{
+		{
+			name: "default value",
+			args: []string{"backup"},
+			expect: &RestoreOption{
+				dir: "backup",
+				in:  os.Stdin,
+				out: os.Stdout,
+			},
+		},
+		{
+			name: "specify all options",
+			args: []string{"--org", "other", "--workspace", "production", "--workspace", "staging", "--include-variable-set", "--delete", "--dry-run", "--auto-approve", "backup"},
+			expect: &RestoreOption{
+				dir:                "backup",
+				organization:       "other",
+				workspaces:         []string{"production", "staging"},
+				includeVariableSet: true,
+				delete:             true,
+				dryRun:             true,
+				autoApprove:        true,
+				in:                 os.Stdin,
+				out:                os.Stdout,
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			app := cli.NewApp()
+			set := flagSet(restoreFlags())
+			set.Parse(tt.args)
+			ctx := cli.NewContext(app, set, nil)
+
+			sut := NewRestoreOption(ctx)
+
+			if !reflect.DeepEqual(tt.expect, sut) {
+				t.Errorf("expect '%+v', got '%+v'", tt.expect, sut)
+			}
+		})
+	}
+}
@@ -1,32 +1,43 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	tfe "github.com/hashicorp/go-tfe"
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v2"
 )
 
+// removeAllConfirmation is the phrase to be typed to delete all variables
+const removeAllConfirmation = "delete all"
+
 type RemoveOption struct {
-	variableKey string
+	filter      *VariableFilter
+	all         bool
+	variableSet string
 	autoApprove bool
+	interactive bool
 	in          io.Reader
 	out         io.Writer
 }
 
 func NewRemoveOption(c *cli.Context) *RemoveOption {
 	var opt = &RemoveOption{}
-	opt.variableKey = c.String("variable")
-	if opt.variableKey == "" {
+	opt.filter = NewVariableFilter(c, c.StringSlice("variable"))
+	opt.all = c.Bool("all")
+	if !opt.filter.hasSelector() && !opt.all {
 		return nil
 	}
 
+	opt.variableSet = c.String("variable-set")
 	opt.autoApprove = c.Bool("auto-approve")
+	opt.interactive = c.Bool("interactive")
 
 	opt.in = os.Stdin
 	opt.out = os.Stdout
@@ -38,6 +49,19 @@ func Remove(c *cli.Context) error {
 	ctx := context.Background()
 	log.Debug().Msg("rm command")
 
+	rmOpt := NewRemoveOption(c)
+	if rmOpt == nil {
+		log.Error().Msg("failed to parse options")
+		return errors.New("failed to parse options: specify --variable, --include, --regex, --category or --all")
+	}
+	if rmOpt.all && rmOpt.filter != nil {
+		return errors.New("--all cannot be specified with other options to select variables")
+	}
+	if err := rmOpt.filter.Validate(); err != nil {
+		return err
+	}
+	log.Debug().Msgf("rmOpt: %+v", rmOpt)
+
 	tfeClient, err := NewTfeClient(c)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to build tfe client")
@@ -45,19 +69,21 @@ func Remove(c *cli.Context) error {
 	}
 
 	organization, workspaceName := updateTerraformCloudWorkspace(organization, workspaceName, ".")
+	if rmOpt.variableSet != "" {
+		variableSet, err := findVariableSet(ctx, organization, rmOpt.variableSet, tfeClient.VariableSets)
+		if err != nil {
+			return err
+		}
+
+		return removeVariableSetVariables(ctx, variableSet.ID, tfeClient.VariableSetVariables, rmOpt)
+	}
+
 	workspace, err := tfeClient.Workspaces.Read(ctx, organization, workspaceName)
 	if err != nil {
 		log.Error().Err(err).Msgf("failed to access workspace %s/%s", organization, workspaceName)
 		return err
 	}
 
-	rmOpt := NewRemoveOption(c)
-	if rmOpt == nil {
-		log.Error().Msg("failed to parse options")
-		return errors.New("failed to parse options")
-	}
-	log.Debug().Msgf("rmOpt: %+v", rmOpt)
-
 	return remove(ctx, workspace.ID, tfeClient.Variables, rmOpt)
 }
 
@@ -68,23 +94,53 @@ func remove(ctx context.Context, workspaceId string, tfeVariables tfe.Variables,
 		return err
 	}
 
-	var targetVariable *tfe.Variable
-	for _, variable := range variables.Items {
-		if variable.Key == rmOpt.variableKey {
-			targetVariable = variable
-			break
-		}
+	return removeVariables(JOURNAL_TARGET_WORKSPACE, workspaceId, variables.Items, func(id string) error {
+		return tfeVariables.Delete(ctx, workspaceId, id)
+	}, rmOpt)
+}
+
+// removeVariableSetVariables delete variables of variable set
+func removeVariableSetVariables(ctx context.Context, variableSetId string, tfeVariableSetVariables tfe.VariableSetVariables, rmOpt *RemoveOption) error {
+	variableList, err := tfeVariableSetVariables.List(ctx, variableSetId, nil)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to list VariableSetVariables ID: %s", variableSetId)
+		return err
+	}
+	variables := []*tfe.Variable{}
+	for _, v := range variableList.Items {
+		variables = append(variables, convertVariableSetVariable(v))
 	}
 
-	if targetVariable == nil {
-		msg := fmt.Sprintf("variable '%s' not found", rmOpt.variableKey)
-		log.Error().Msg(msg)
-		return fmt.Errorf(msg)
+	return removeVariables(JOURNAL_TARGET_VARIABLE_SET, variableSetId, variables, func(id string) error {
+		return tfeVariableSetVariables.Delete(ctx, variableSetId, id)
+	}, rmOpt)
+}
+
+// removeVariables plan deletion of selected variables, confirm and delete them
+// deletion continues on failure and errors of all keys are returned
+func removeVariables(target string, targetId string, variables []*tfe.Variable, deleteVariable func(id string) error, rmOpt *RemoveOption) error {
+	planned, err := planRemove(variables, rmOpt)
+	if err != nil {
+		return err
 	}
-	if !rmOpt.autoApprove {
-		fmt.Fprintf(rmOpt.out, "delete variable: %s", targetVariable.Key)
-		fmt.Print(rmOpt.out, "Are you shure you want to delete variable in Terraform Cloud? [y/n]: ")
-		res, err := confirm(rmOpt.in)
+	if len(planned) == 0 {
+		fmt.Fprintln(rmOpt.out, "No changes.")
+		return nil
+	}
+
+	if rmOpt.interactive && !rmOpt.autoApprove {
+		planned, err = interactiveApprove(rmOpt.in, rmOpt.out, planned)
+		if err != nil {
+			return err
+		}
+		if len(planned) == 0 {
+			return nil
+		}
+	} else if !rmOpt.autoApprove {
+		if err := requireTerminal(rmOpt.in); err != nil {
+			return err
+		}
+		res, err := confirmRemove(rmOpt, planned, len(variables))
 		if err != nil {
 			return err
 		}
@@ -93,11 +149,58 @@ func remove(ctx context.Context, workspaceId string, tfeVariables tfe.Variables,
 		}
 	}
 
-	err = tfeVariables.Delete(ctx, workspaceId, targetVariable.ID)
+	return applyOperations(target, targetId, planned, rmOpt.out, func(variable *PushVariable) (string, error) {
+		return variable.id, deleteVariable(variable.id)
+	})
+}
+
+// planRemove return delete operations of variables selected by options
+// return error if variable specified by exact key does not exist
+func planRemove(variables []*tfe.Variable, rmOpt *RemoveOption) ([]*PushVariable, error) {
+	if rmOpt.filter != nil {
+	keyLoop:
+		for _, key := range rmOpt.filter.keys {
+			for _, variable := range variables {
+				if variable.Key == key {
+					continue keyLoop
+				}
+			}
+			msg := fmt.Sprintf("variable '%s' not found", key)
+			log.Error().Msg(msg)
+			return nil, errors.New(msg)
+		}
+	}
+
+	planned := []*PushVariable{}
+	for _, variable := range rmOpt.filter.Filter(variables) {
+		planned = append(planned, &PushVariable{
+			operation: PUSH_OPERATION_DELETE,
+			id:        variable.ID,
+			previous:  variable,
+		})
+	}
+
+	return planned, nil
+}
+
+// confirmRemove print every variable to be deleted and ask for confirmation
+// deleting all variables requires typing the confirmation phrase instead of y/n,
+// even if they are selected by filter rather than --all
+func confirmRemove(rmOpt *RemoveOption, planned []*PushVariable, total int) (bool, error) {
+	for _, variable := range planned {
+		fmt.Fprintf(rmOpt.out, "delete variable: %s\n", variable.key())
+	}
+
+	if !rmOpt.all && len(planned) < total {
+		fmt.Fprint(rmOpt.out, "Are you sure you want to delete variables in Terraform Cloud? [y/n]: ")
+		return confirm(rmOpt.in)
+	}
+
+	fmt.Fprintf(rmOpt.out, "All %d variables will be deleted. Type '%s' to confirm: ", len(planned), removeAllConfirmation)
+	input, err := readLine(bufio.NewReader(rmOpt.in))
 	if err != nil {
-		log.Error().Err(err).Msg("failed to delete variable")
-		return err
+		return false, err
 	}
 
-	return nil
+	return strings.EqualFold(input, removeAllConfirmation), nil
 }
@@ -33,7 +33,7 @@ func TestCmdRemove(t *testing.T) {
 		{
 			name:        "remove variable",
 			workspaceId: "ws-remove-variable",
-			removeOpt:   &RemoveOption{variableKey: "environment", autoApprove: true},
+			removeOpt:   &RemoveOption{filter: &VariableFilter{keys: []string{"environment"}}, autoApprove: true},
 			setClient: func(mc *mocks.MockVariables) {
 				mc.EXPECT().List(gomock.Any(), "ws-remove-variable", nil).Return(&tfe.VariableList{
 					Items: []*tfe.Variable{
@@ -50,7 +50,7 @@ func TestCmdRemove(t *testing.T) {
 		{
 			name:        "return error if variable not exist",
 			workspaceId: "ws-specified-variable-not-exist",
-			removeOpt:   &RemoveOption{variableKey: "environment", autoApprove: true},
+			removeOpt:   &RemoveOption{filter: &VariableFilter{keys: []string{"environment"}}, autoApprove: true},
 			setClient: func(mc *mocks.MockVariables) {
 				mc.EXPECT().List(gomock.Any(), "ws-specified-variable-not-exist", nil).Return(&tfe.VariableList{
 					Items: []*tfe.Variable{
@@ -74,7 +74,7 @@ func TestCmdRemove(t *testing.T) {
 		{
 			name:        "return error if delete variable in tfc failed",
 			workspaceId: "ws-error-raised-in-tfc",
-			removeOpt:   &RemoveOption{variableKey: "environment", autoApprove: true},
+			removeOpt:   &RemoveOption{filter: &VariableFilter{keys: []string{"environment"}}, autoApprove: true},
 			setClient: func(mc *mocks.MockVariables) {
 				mc.EXPECT().List(gomock.Any(), "ws-error-raised-in-tfc", nil).Return(&tfe.VariableList{
 					Items: []*tfe.Variable{
@@ -99,7 +99,7 @@ func TestCmdRemove(t *testing.T) {
 		{
 			name:        "require approve if auto-approve is not specified",
 			workspaceId: "w-require-approve",
-			removeOpt:   &RemoveOption{variableKey: "environment", autoApprove: false},
+			removeOpt:   &RemoveOption{filter: &VariableFilter{keys: []string{"environment"}}, autoApprove: false},
 			setClient: func(mc *mocks.MockVariables) {
 				mc.EXPECT().List(gomock.Any(), "w-require-approve", nil).Return(&tfe.VariableList{
 					Items: []*tfe.Variable{
@@ -118,13 +118,13 @@ func TestCmdRemove(t *testing.T) {
 				mc.EXPECT().Delete(gomock.Any(), "w-require-approve", "v-environment").Return(nil)
 			},
 			input:   "yes\n",
-			expect:  "delete variable: environment",
+			expect:  "delete variable: environment\nAre you sure you want to delete variables in Terraform Cloud? [y/n]: ",
 			wantErr: false,
 		},
 		{
 			name:        "do nothing if approve declined",
 			workspaceId: "w-decline-approve",
-			removeOpt:   &RemoveOption{variableKey: "environment", autoApprove: false},
+			removeOpt:   &RemoveOption{filter: &VariableFilter{keys: []string{"environment"}}, autoApprove: false},
 			setClient: func(mc *mocks.MockVariables) {
 				mc.EXPECT().List(gomock.Any(), "w-decline-approve", nil).Return(&tfe.VariableList{
 					Items: []*tfe.Variable{
@@ -142,13 +142,13 @@ func TestCmdRemove(t *testing.T) {
 				}, nil)
 			},
 			input:   "n\n",
-			expect:  "delete variable: environment",
+			expect:  "delete variable: environment\nAre you sure you want to delete variables in Terraform Cloud? [y/n]: ",
 			wantErr: false,
 		},
 		{
 			name:        "do nothing if approve not input",
 			workspaceId: "w-noinput-approve",
-			removeOpt:   &RemoveOption{variableKey: "environment", autoApprove: false},
+			removeOpt:   &RemoveOption{filter: &VariableFilter{keys: []string{"environment"}}, autoApprove: false},
 			setClient: func(mc *mocks.MockVariables) {
 				mc.EXPECT().List(gomock.Any(), "w-noinput-approve", nil).Return(&tfe.VariableList{
 					Items: []*tfe.Variable{
@@ -166,14 +166,169 @@ func TestCmdRemove(t *testing.T) {
 				}, nil)
 			},
 			input:     "",
-			expect:    "delete variable: environment",
+			expect:    "delete variable: environment\nAre you sure you want to delete variables in Terraform Cloud? [y/n]: ",
 			wantErr:   true,
-			expectErr: "EOF",
+			expectErr: "no input for confirmation",
+		},
+		{
+			name:        "remove multiple variables",
+			workspaceId: "ws-remove-multiple-variables",
+			removeOpt:   &RemoveOption{filter: &VariableFilter{keys: []string{"environment", "db_host"}}, autoApprove: true},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().List(gomock.Any(), "ws-remove-multiple-variables", nil).Return(&tfe.VariableList{
+					Items: []*tfe.Variable{
+						{ID: "v-environment", Key: "environment", Value: "test"},
+						{ID: "v-db_host", Key: "db_host", Value: "localhost"},
+						{ID: "v-db_password", Key: "db_password", Sensitive: true},
+						{ID: "v-DB_URL", Key: "DB_URL", Value: "postgres://localhost", Category: tfe.CategoryEnv},
+					},
+				}, nil)
+				mc.EXPECT().Delete(gomock.Any(), "ws-remove-multiple-variables", "v-environment").Return(nil)
+				mc.EXPECT().Delete(gomock.Any(), "ws-remove-multiple-variables", "v-db_host").Return(nil)
+			},
+		},
+		{
+			name:        "remove variables matching glob pattern",
+			workspaceId: "ws-remove-glob-variables",
+			removeOpt:   &RemoveOption{filter: &VariableFilter{includes: []string{"db_*"}}, autoApprove: true},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().List(gomock.Any(), "ws-remove-glob-variables", nil).Return(&tfe.VariableList{
+					Items: []*tfe.Variable{
+						{ID: "v-environment", Key: "environment", Value: "test"},
+						{ID: "v-db_host", Key: "db_host", Value: "localhost"},
+						{ID: "v-db_password", Key: "db_password", Sensitive: true},
+						{ID: "v-DB_URL", Key: "DB_URL", Value: "postgres://localhost", Category: tfe.CategoryEnv},
+					},
+				}, nil)
+				mc.EXPECT().Delete(gomock.Any(), "ws-remove-glob-variables", "v-db_host").Return(nil)
+				mc.EXPECT().Delete(gomock.Any(), "ws-remove-glob-variables", "v-db_password").Return(nil)
+			},
+		},
+		{
+			name:        "remove variables of category",
+			workspaceId: "ws-remove-category-variables",
+			removeOpt:   &RemoveOption{filter: &VariableFilter{category: "env"}, autoApprove: true},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().List(gomock.Any(), "ws-remove-category-variables", nil).Return(&tfe.VariableList{
+					Items: []*tfe.Variable{
+						{ID: "v-environment", Key: "environment", Value: "test"},
+						{ID: "v-db_host", Key: "db_host", Value: "localhost"},
+						{ID: "v-db_password", Key: "db_password", Sensitive: true},
+						{ID: "v-DB_URL", Key: "DB_URL", Value: "postgres://localhost", Category: tfe.CategoryEnv},
+					},
+				}, nil)
+				mc.EXPECT().Delete(gomock.Any(), "ws-remove-category-variables", "v-DB_URL").Return(nil)
+			},
+		},
+		{
+			name:        "print no changes if no variable matches",
+			workspaceId: "ws-remove-no-match-variables",
+			removeOpt:   &RemoveOption{filter: &VariableFilter{includes: []string{"aws_*"}}, autoApprove: true},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().List(gomock.Any(), "ws-remove-no-match-variables", nil).Return(&tfe.VariableList{
+					Items: []*tfe.Variable{
+						{ID: "v-environment", Key: "environment", Value: "test"},
+						{ID: "v-db_host", Key: "db_host", Value: "localhost"},
+						{ID: "v-db_password", Key: "db_password", Sensitive: true},
+						{ID: "v-DB_URL", Key: "DB_URL", Value: "postgres://localhost", Category: tfe.CategoryEnv},
+					},
+				}, nil)
+			},
+			expect: "No changes.\n",
+		},
+		{
+			name:        "continue deleting and report failed keys",
+			workspaceId: "ws-remove-partially-failed",
+			removeOpt:   &RemoveOption{filter: &VariableFilter{includes: []string{"db_*"}}, autoApprove: true},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().List(gomock.Any(), "ws-remove-partially-failed", nil).Return(&tfe.VariableList{
+					Items: []*tfe.Variable{
+						{ID: "v-environment", Key: "environment", Value: "test"},
+						{ID: "v-db_host", Key: "db_host", Value: "localhost"},
+						{ID: "v-db_password", Key: "db_password", Sensitive: true},
+						{ID: "v-DB_URL", Key: "DB_URL", Value: "postgres://localhost", Category: tfe.CategoryEnv},
+					},
+				}, nil)
+				mc.EXPECT().Delete(gomock.Any(), "ws-remove-partially-failed", "v-db_host").Return(errors.New("permission denied"))
+				mc.EXPECT().Delete(gomock.Any(), "ws-remove-partially-failed", "v-db_password").Return(nil)
+			},
+			wantErr:   true,
+			expectErr: "failed to delete db_host: permission denied",
+		},
+		{
+			name:        "remove all variables with confirmation phrase",
+			workspaceId: "ws-remove-all-variables",
+			removeOpt:   &RemoveOption{all: true},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().List(gomock.Any(), "ws-remove-all-variables", nil).Return(&tfe.VariableList{
+					Items: []*tfe.Variable{
+						{ID: "v-environment", Key: "environment", Value: "test"},
+						{ID: "v-db_host", Key: "db_host", Value: "localhost"},
+						{ID: "v-db_password", Key: "db_password", Sensitive: true},
+						{ID: "v-DB_URL", Key: "DB_URL", Value: "postgres://localhost", Category: tfe.CategoryEnv},
+					},
+				}, nil)
+				mc.EXPECT().Delete(gomock.Any(), "ws-remove-all-variables", "v-environment").Return(nil)
+				mc.EXPECT().Delete(gomock.Any(), "ws-remove-all-variables", "v-db_host").Return(nil)
+				mc.EXPECT().Delete(gomock.Any(), "ws-remove-all-variables", "v-db_password").Return(nil)
+				mc.EXPECT().Delete(gomock.Any(), "ws-remove-all-variables", "v-DB_URL").Return(nil)
+			},
+			input:  "delete all\n",
+			expect: "delete variable: environment\ndelete variable: db_host\ndelete variable: db_password\ndelete variable: DB_URL\nAll 4 variables will be deleted. Type 'delete all' to confirm: ",
+		},
+		{
+			name:        "do nothing if confirmation phrase for all variables not matched",
+			workspaceId: "ws-remove-all-declined",
+			removeOpt:   &RemoveOption{all: true},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().List(gomock.Any(), "ws-remove-all-declined", nil).Return(&tfe.VariableList{
+					Items: []*tfe.Variable{
+						{ID: "v-environment", Key: "environment", Value: "test"},
+						{ID: "v-db_host", Key: "db_host", Value: "localhost"},
+						{ID: "v-db_password", Key: "db_password", Sensitive: true},
+						{ID: "v-DB_URL", Key: "DB_URL", Value: "postgres://localhost", Category: tfe.CategoryEnv},
+					},
+				}, nil)
+			},
+			input:  "yes\n",
+			expect: "delete variable: environment\ndelete variable: db_host\ndelete variable: db_password\ndelete variable: DB_URL\nAll 4 variables will be deleted. Type 'delete all' to confirm: ",
+		},
+		{
+			name:        "require confirmation phrase if filter selects all variables",
+			workspaceId: "ws-remove-all-matched",
+			removeOpt:   &RemoveOption{filter: &VariableFilter{includes: []string{"*"}, excludes: []string{"aws_*"}}},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().List(gomock.Any(), "ws-remove-all-matched", nil).Return(&tfe.VariableList{
+					Items: []*tfe.Variable{
+						{ID: "v-environment", Key: "environment", Value: "test"},
+						{ID: "v-db_host", Key: "db_host", Value: "localhost"},
+					},
+				}, nil)
+			},
+			input:  "y\n",
+			expect: "delete variable: environment\ndelete variable: db_host\nAll 2 variables will be deleted. Type 'delete all' to confirm: ",
+		},
+		{
+			name:        "return error if one of variables not exist",
+			workspaceId: "ws-remove-one-not-exist",
+			removeOpt:   &RemoveOption{filter: &VariableFilter{keys: []string{"environment", "region"}}, autoApprove: true},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().List(gomock.Any(), "ws-remove-one-not-exist", nil).Return(&tfe.VariableList{
+					Items: []*tfe.Variable{
+						{ID: "v-environment", Key: "environment", Value: "test"},
+						{ID: "v-db_host", Key: "db_host", Value: "localhost"},
+						{ID: "v-db_password", Key: "db_password", Sensitive: true},
+						{ID: "v-DB_URL", Key: "DB_URL", Value: "postgres://localhost", Category: tfe.CategoryEnv},
+					},
+				}, nil)
+			},
+			wantErr:   true,
+			expectErr: "variable 'region' not found",
 		},
 		{
 			name:        "return error if failed to list variables",
 			workspaceId: "w-error-list-variable",
-			removeOpt:   &RemoveOption{variableKey: "environment", autoApprove: false},
+			removeOpt:   &RemoveOption{filter: &VariableFilter{keys: []string{"environment"}}, autoApprove: false},
 			setClient: func(mc *mocks.MockVariables) {
 				mc.EXPECT().List(gomock.Any(), "w-error-list-variable", nil).Return(nil, errors.New("failed to list variables"))
 			},
@@ -210,6 +365,37 @@ func TestCmdRemove(t *testing.T) {
 	}
 }
 
+func TestRemoveVariableSetVariables(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVariableSetVariables := mocks.NewMockVariableSetVariables(ctrl)
+	mockVariableSetVariables.EXPECT().List(gomock.Any(), "varset-common", nil).Return(&tfe.VariableSetVariableList{
+		Items: []*tfe.VariableSetVariable{
+			{ID: "v-environment", Key: "environment", Value: "test"},
+			{ID: "v-aws_region", Key: "aws_region", Value: "ap-northeast-1"},
+			{ID: "v-aws_account", Key: "aws_account", Value: "123456789012"},
+		},
+	}, nil)
+	mockVariableSetVariables.EXPECT().Delete(gomock.Any(), "varset-common", "v-aws_region").Return(nil)
+	mockVariableSetVariables.EXPECT().Delete(gomock.Any(), "varset-common", "v-aws_account").Return(nil)
+
+	outBuf := new(bytes.Buffer)
+	rmOpt := &RemoveOption{
+		filter:      &VariableFilter{includes: []string{"aws_*"}},
+		variableSet: "common",
+		in:          strings.NewReader("y\n"),
+		out:         outBuf,
+	}
+
+	err := removeVariableSetVariables(context.TODO(), "varset-common", mockVariableSetVariables, rmOpt)
+	if err != nil {
+		t.Errorf("expect no error, got error: %v", err)
+	}
+	expect := "delete variable: aws_region\ndelete variable: aws_account\nAre you sure you want to delete variables in Terraform Cloud? [y/n]: "
+	if outBuf.String() != expect {
+		t.Errorf("expect '%s', got '%s'", expect, outBuf.String())
+	}
+}
+
 func TestNewRemoveOption(t *testing.T) {
 	cases := []struct {
 		name   string
@@ -225,7 +411,7 @@ func TestNewRemoveOption(t *testing.T) {
 			name: "specify variable key",
 			args: []string{"--variable", "environment"},
 			expect: &RemoveOption{
-				variableKey: "environment",
+				filter:      &VariableFilter{keys: []string{"environment"}},
 				autoApprove: false,
 				in:          os.Stdin,
 				out:         os.Stdout,
@@ -236,11 +422,36 @@ func TestNewRemoveOption(t *testing.T) {
 			args:   []string{"--auto-approve"},
 			expect: nil,
 		},
+		{
+			name:   "specify only exclude",
+			args:   []string{"--exclude", "aws_*"},
+			expect: nil,
+		},
+		{
+			name: "specify multiple variables and patterns",
+			args: []string{"--variable", "environment", "--variable", "region", "--include", "db_*", "--category", "env"},
+			expect: &RemoveOption{
+				filter:      &VariableFilter{keys: []string{"environment", "region"}, includes: []string{"db_*"}, category: "env"},
+				autoApprove: false,
+				in:          os.Stdin,
+				out:         os.Stdout,
+			},
+		},
+		{
+			name: "specify all option and variable set",
+			args: []string{"--all", "--variable-set", "common"},
+			expect: &RemoveOption{
+				all:         true,
+				variableSet: "common",
+				in:          os.Stdin,
+				out:         os.Stdout,
+			},
+		},
 		{
 			name: "specify all variables",
 			args: []string{"--variable", "environment", "--auto-approve"},
 			expect: &RemoveOption{
-				variableKey: "environment",
+				filter:      &VariableFilter{keys: []string{"environment"}},
 				autoApprove: true,
 				in:          os.Stdin,
 				out:         os.Stdout,
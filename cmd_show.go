@@ -10,32 +10,42 @@ import (
 	"strings"
 
 	tfe "github.com/hashicorp/go-tfe"
-	"github.com/hashicorp/hcl/v2/hclparse"
-	"github.com/hashicorp/hcl/v2/hclwrite"
-	"github.com/olekukonko/tablewriter"
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v2"
-	"github.com/zclconf/go-cty/cty"
 )
 
 type ShowOption struct {
 	varFile            string
-	variableKey        string
+	filter             *VariableFilter
 	local              bool
 	includeEnv         bool
 	includeVariableSet bool
 	format             string
+	sensitive          string
+	template           string
+	templateFile       string
+	source             string
+	sort               string
+	columns            []string
+	reveal             bool
 }
 
 func NewShowOption(c *cli.Context) *ShowOption {
 	var opt = &ShowOption{}
 
 	opt.varFile = c.String("var-file")
-	opt.variableKey = c.String("variable")
+	opt.filter = NewVariableFilter(c, c.StringSlice("variable"))
 	opt.local = c.Bool("local")
 	opt.includeEnv = c.Bool("include-env")
 	opt.includeVariableSet = c.Bool("include-variable-set")
 	opt.format = c.String("format")
+	opt.sensitive = c.String("sensitive")
+	opt.template = c.String("template")
+	opt.templateFile = c.String("template-file")
+	opt.source = c.String("source")
+	opt.sort = c.String("sort")
+	opt.columns = c.StringSlice("columns")
+	opt.reveal = c.Bool("reveal")
 
 	return opt
 }
@@ -54,7 +64,7 @@ func (e *FormatType) Set(value string) error {
 		}
 	}
 
-	return fmt.Errorf("allowed values are %s", strings.Join(e.Enum, ", "))
+	return fmt.Errorf("invalid value '%s', allowed values are %s", value, strings.Join(e.Enum, ", "))
 }
 
 func (e FormatType) String() string {
@@ -70,6 +80,28 @@ func Show(c *cli.Context) error {
 	log.Debug().Msg("show command")
 
 	showOpt := NewShowOption(c)
+	if err := showOpt.filter.Validate(); err != nil {
+		return err
+	}
+	if showOpt.templateFile != "" {
+		if showOpt.template != "" {
+			return errors.New("--template and --template-file cannot be specified together")
+		}
+		src, err := os.ReadFile(showOpt.templateFile)
+		if err != nil {
+			log.Error().Err(err).Msgf("cannot read template file: %s", showOpt.templateFile)
+			return err
+		}
+		showOpt.template = string(src)
+	}
+	if len(showOpt.columns) != 0 {
+		if showOpt.format != "table" {
+			return errors.New("--columns is available only with table format")
+		}
+		if err := validateColumns(showOpt.columns); err != nil {
+			return err
+		}
+	}
 	workspaceId := ""
 	var Variables tfe.Variables
 	var VariableSets tfe.VariableSets
@@ -104,62 +136,54 @@ func Show(c *cli.Context) error {
 func show(ctx context.Context, workspaceId string, tfeVariables tfe.Variables, tfeVariableSets tfe.VariableSets, tfeVariableSetVariables tfe.VariableSetVariables, showOpt *ShowOption, w io.Writer) error {
 	var vars *tfe.VariableList
 	var err error
+	sources := &VariableSources{local: showOpt.local}
 
 	if showOpt.local {
 		// terraform.tfvarsを読んで vars 変数に格納する
 		log.Debug().Msg("local variable show command")
-		vars = &tfe.VariableList{}
-		vars.Items = []*tfe.Variable{}
-
-		p := hclparse.NewParser()
-		file, diags := p.ParseHCLFile(showOpt.varFile)
-		if diags.HasErrors() {
-			return errors.New(diags.Error())
-		}
-		attrs, _ := file.Body.JustAttributes()
-		for _, attr := range SortAttributes(attrs) {
-			val, _ := attr.Expr.Value(nil)
-			tfVariable := &tfe.Variable{
-				Key:   attr.Name,
-				Value: String(val),
-			}
-			if !IsPrimitive(val) {
-				tfVariable.HCL = true
-			}
-
-			vars.Items = append(vars.Items, tfVariable)
+		vf, err := NewTfvarsFile(showOpt.varFile)
+		if err != nil {
+			log.Error().Err(err).Msgf("failed to parse var-file: %s", showOpt.varFile)
+			return err
 		}
-
+		vars = &tfe.VariableList{Items: vf.vars}
 	} else {
 		vars, err = tfeVariables.List(ctx, workspaceId, nil)
 		if err != nil {
 			log.Error().Err(err).Msg("failed to list variables")
 			return err
 		}
-		if showOpt.includeVariableSet {
-			variableSetVariables, err := listVariableSetVariables(ctx, workspaceId, tfeVariableSets, tfeVariableSetVariables)
+		if showOpt.includeVariableSet || showOpt.source == SOURCE_VARSET {
+			variableSetVariables, variableSets, err := listVariableSetVariablesWithSource(ctx, workspaceId, tfeVariableSets, tfeVariableSetVariables)
 			if err != nil {
 				log.Error().Err(err).Msg("failed to list VariableSetVariables")
 				return err
 			}
 			vars.Items = append(vars.Items, variableSetVariables...)
+			sources.variableSets = variableSets
 		}
-		if !showOpt.includeEnv {
+		if !showOpt.includeEnv && !showOpt.requireEnv() {
 			vars.Items = FilterEnv(vars.Items)
 		}
 	}
 
-	filteredVars := []*tfe.Variable{}
-	for _, v := range vars.Items {
-		if showOpt.variableKey != "" && showOpt.variableKey != v.Key {
-			continue
-		}
-
-		filteredVars = append(filteredVars, v)
+	variables := selectSource(showOpt.filter.Filter(vars.Items), sources, showOpt.source)
+	sortVariables(variables, sources, showOpt.sort)
+	if !showOpt.reveal && !showOpt.writesValues() {
+		variables = redactSecrets(variables)
 	}
-	printVariable(w, filteredVars, showOpt)
 
-	return nil
+	return printVariable(w, variables, sources, showOpt)
+}
+
+// requireEnv return true if env Category variables are always shown by format or filter
+func (opt *ShowOption) requireEnv() bool {
+	return opt.format == "dotenv" || opt.format == "export" || opt.filter.requireEnv()
+}
+
+// writesValues return true if format is used to write variables into file or environment, whose values are never masked
+func (opt *ShowOption) writesValues() bool {
+	return opt.format == "tfvars" || opt.format == "dotenv" || opt.format == "export"
 }
 
 func requireTfcAccess(opt *ShowOption) bool {
@@ -167,7 +191,7 @@ func requireTfcAccess(opt *ShowOption) bool {
 	return !opt.local
 }
 
-func printVariable(w io.Writer, variables []*tfe.Variable, opt *ShowOption) {
+func printVariable(w io.Writer, variables []*tfe.Variable, sources *VariableSources, opt *ShowOption) error {
 	switch opt.format {
 	case "detail":
 		for _, v := range variables {
@@ -178,31 +202,40 @@ func printVariable(w io.Writer, variables []*tfe.Variable, opt *ShowOption) {
 			fmt.Fprintf(w, "\n")
 		}
 	case "tfvars":
-		f := hclwrite.NewEmptyFile()
-		rootBody := f.Body()
-
-		for _, v := range variables {
-			if v.Sensitive {
-				rootBody.AppendUnstructuredTokens(generateComment(v.Key))
-			} else if v.HCL {
-				rootBody.SetAttributeValue(v.Key, CtyValue(v.Value))
-			} else {
-				rootBody.SetAttributeValue(v.Key, cty.StringVal(v.Value))
-			}
+		var localFile []byte
+		if opt.sensitive == SENSITIVE_KEEP_LOCAL {
+			localFile, _ = readDecryptedFile(opt.varFile)
+		}
+		sensitiveOpt := NewSensitiveOption(opt.sensitive, opt.varFile, localFile)
+		f, err := BuildHCLFile(FilterEnv(variables), nil, "", sensitiveOpt)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to build tfvars")
+			return err
 		}
 
 		fmt.Fprintf(w, "%s", f.Bytes())
-	case "table":
-		var data [][]string
-		for _, v := range variables {
-			row := []string{v.Key, v.Value, strconv.FormatBool(v.Sensitive), v.Description}
-			data = append(data, row)
+	case "dotenv":
+		sensitiveOpt := &SensitiveOption{strategy: opt.sensitive}
+		fmt.Fprintf(w, "%s", BuildDotenvFile(variables, sensitiveOpt, nil))
+	case "export":
+		writeVariablesExport(w, variables)
+	case "json":
+		return writeVariablesJSON(w, newVariableOutputs(variables, sources))
+	case "yaml":
+		return writeVariablesYAML(w, newVariableOutputs(variables, sources))
+	case "csv":
+		return writeVariablesCSV(w, newVariableOutputs(variables, sources))
+	case "template":
+		if opt.template == "" {
+			return errors.New("--template or --template-file is required for template format")
 		}
-		table := tablewriter.NewWriter(w)
-		table.SetHeader([]string{"Key", "Value", "Sensitive", "Description"})
-		table.AppendBulk(data)
-		table.Render()
+		return writeVariablesTemplate(w, newVariableOutputs(variables, sources), opt.template)
+	case "table":
+		return writeVariablesTable(w, newVariableOutputs(variables, sources), opt.columns)
 	default:
 		log.Error().Msgf("unknown format %s specified", opt.format)
+		return fmt.Errorf("unknown format '%s'", opt.format)
 	}
+
+	return nil
 }
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -118,7 +120,7 @@ func TestCmdShow(t *testing.T) {
 		{
 			name:        "show specified variable",
 			workspaceId: "w-test-multiple-variables-filter-variable-workspace",
-			showOpt:     &ShowOption{variableKey: "var2", format: "detail"},
+			showOpt:     &ShowOption{filter: &VariableFilter{keys: []string{"var2"}}, format: "detail"},
 			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
 				mc.EXPECT().
 					List(context.TODO(), "w-test-multiple-variables-filter-variable-workspace", nil).
@@ -179,6 +181,464 @@ func TestCmdShow(t *testing.T) {
 			wantErr:   false,
 			expectErr: "",
 		},
+		{
+			name:        "show variables with tfvars format and placeholder for sensitive",
+			workspaceId: "w-test-variables-tfvars-placeholder-workspace",
+			showOpt:     &ShowOption{format: "tfvars", sensitive: SENSITIVE_PLACEHOLDER, varFile: "testdata/config/terraform.tfvars"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-tfvars-placeholder-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "var1",
+								Value: "value1",
+							},
+							{
+								Key:       "db_port",
+								HCL:       true,
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "var1    = \"value1\"\ndb_port = 0\n",
+			wantErr:   false,
+			expectErr: "",
+		},
+		{
+			name:        "show env variables with dotenv format",
+			workspaceId: "w-test-variables-dotenv-workspace",
+			showOpt:     &ShowOption{format: "dotenv"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-dotenv-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "var1",
+								Value: "value1",
+							},
+							{
+								Key:      "var5",
+								Value:    "val5",
+								Category: tfe.CategoryEnv,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "var5=\"val5\"\n",
+			wantErr:   false,
+			expectErr: "",
+		},
+		{
+			name:        "show only terraform variables with tfvars format",
+			workspaceId: "w-test-variables-tfvars-include-env-workspace",
+			showOpt:     &ShowOption{format: "tfvars", includeEnv: true},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-tfvars-include-env-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "var1",
+								Value: "value1",
+							},
+							{
+								Key:      "var5",
+								Value:    "val5",
+								Category: tfe.CategoryEnv,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "var1 = \"value1\"\n",
+			wantErr:   false,
+			expectErr: "",
+		},
+		{
+			name:        "show variables with json format",
+			workspaceId: "w-test-variables-json-workspace",
+			showOpt:     &ShowOption{format: "json"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-json-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:          "var-1",
+								Key:         "var1",
+								Value:       "it's \"quoted\"",
+								Description: "multi\nline",
+								Category:    tfe.CategoryTerraform,
+							},
+							{
+								ID:        "var-2",
+								Key:       "var2",
+								Category:  tfe.CategoryTerraform,
+								Sensitive: true,
+							},
+							{
+								ID:       "var-3",
+								Key:      "var3",
+								Value:    "[\"a\", \"b\"]",
+								Category: tfe.CategoryTerraform,
+								HCL:      true,
+							},
+							{
+								ID:       "var-5",
+								Key:      "VAR5",
+								Value:    "$HOME",
+								Category: tfe.CategoryEnv,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: `[
+  {
+    "id": "var-1",
+    "key": "var1",
+    "value": "it's \"quoted\"",
+    "description": "multi\nline",
+    "category": "terraform",
+    "hcl": false,
+    "sensitive": false,
+    "source": "workspace"
+  },
+  {
+    "id": "var-2",
+    "key": "var2",
+    "value": "",
+    "description": "",
+    "category": "terraform",
+    "hcl": false,
+    "sensitive": true,
+    "source": "workspace"
+  },
+  {
+    "id": "var-3",
+    "key": "var3",
+    "value": "[\"a\", \"b\"]",
+    "description": "",
+    "category": "terraform",
+    "hcl": true,
+    "sensitive": false,
+    "source": "workspace"
+  }
+]
+`,
+			wantErr:   false,
+			expectErr: "",
+		},
+		{
+			name:        "show variables with yaml format",
+			workspaceId: "w-test-variables-yaml-workspace",
+			showOpt:     &ShowOption{format: "yaml", includeEnv: true},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-yaml-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:          "var-1",
+								Key:         "var1",
+								Value:       "it's \"quoted\"",
+								Description: "multi\nline",
+								Category:    tfe.CategoryTerraform,
+							},
+							{
+								ID:        "var-2",
+								Key:       "var2",
+								Category:  tfe.CategoryTerraform,
+								Sensitive: true,
+							},
+							{
+								ID:       "var-3",
+								Key:      "var3",
+								Value:    "[\"a\", \"b\"]",
+								Category: tfe.CategoryTerraform,
+								HCL:      true,
+							},
+							{
+								ID:       "var-5",
+								Key:      "VAR5",
+								Value:    "$HOME",
+								Category: tfe.CategoryEnv,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: `- id: var-1
+  key: var1
+  value: it's "quoted"
+  description: |-
+    multi
+    line
+  category: terraform
+  hcl: false
+  sensitive: false
+  source: workspace
+- id: var-2
+  key: var2
+  value: ""
+  description: ""
+  category: terraform
+  hcl: false
+  sensitive: true
+  source: workspace
+- id: var-3
+  key: var3
+  value: '["a", "b"]'
+  description: ""
+  category: terraform
+  hcl: true
+  sensitive: false
+  source: workspace
+- id: var-5
+  key: VAR5
+  value: $HOME
+  description: ""
+  category: env
+  hcl: false
+  sensitive: false
+  source: workspace
+`,
+			wantErr:   false,
+			expectErr: "",
+		},
+		{
+			name:        "show variables with csv format",
+			workspaceId: "w-test-variables-csv-workspace",
+			showOpt:     &ShowOption{format: "csv"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-csv-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:          "var-1",
+								Key:         "var1",
+								Value:       "it's \"quoted\"",
+								Description: "multi\nline",
+								Category:    tfe.CategoryTerraform,
+							},
+							{
+								ID:        "var-2",
+								Key:       "var2",
+								Category:  tfe.CategoryTerraform,
+								Sensitive: true,
+							},
+							{
+								ID:       "var-3",
+								Key:      "var3",
+								Value:    "[\"a\", \"b\"]",
+								Category: tfe.CategoryTerraform,
+								HCL:      true,
+							},
+							{
+								ID:       "var-5",
+								Key:      "VAR5",
+								Value:    "$HOME",
+								Category: tfe.CategoryEnv,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "id,key,value,description,category,hcl,sensitive,source,variable_set\nvar-1,var1,\"it's \"\"quoted\"\"\",\"multi\nline\",terraform,false,false,workspace,\nvar-2,var2,,,terraform,false,true,workspace,\nvar-3,var3,\"[\"\"a\"\", \"\"b\"\"]\",,terraform,true,false,workspace,\n",
+			wantErr:   false,
+			expectErr: "",
+		},
+		{
+			name:        "show variables with export format",
+			workspaceId: "w-test-variables-export-workspace",
+			showOpt:     &ShowOption{format: "export"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-export-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:          "var-1",
+								Key:         "var1",
+								Value:       "it's \"quoted\"",
+								Description: "multi\nline",
+								Category:    tfe.CategoryTerraform,
+							},
+							{
+								ID:        "var-2",
+								Key:       "var2",
+								Category:  tfe.CategoryTerraform,
+								Sensitive: true,
+							},
+							{
+								ID:       "var-3",
+								Key:      "var3",
+								Value:    "[\"a\", \"b\"]",
+								Category: tfe.CategoryTerraform,
+								HCL:      true,
+							},
+							{
+								ID:       "var-5",
+								Key:      "VAR5",
+								Value:    "$HOME",
+								Category: tfe.CategoryEnv,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: `export TF_VAR_var1='it'\''s "quoted"'
+# export TF_VAR_var2=***
+export TF_VAR_var3='["a", "b"]'
+export VAR5='$HOME'
+`,
+			wantErr:   false,
+			expectErr: "",
+		},
+		{
+			name:        "return error with unknown format",
+			workspaceId: "w-test-variables-unknown-format-workspace",
+			showOpt:     &ShowOption{format: "xml"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-unknown-format-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:          "var-1",
+								Key:         "var1",
+								Value:       "it's \"quoted\"",
+								Description: "multi\nline",
+								Category:    tfe.CategoryTerraform,
+							},
+							{
+								ID:        "var-2",
+								Key:       "var2",
+								Category:  tfe.CategoryTerraform,
+								Sensitive: true,
+							},
+							{
+								ID:       "var-3",
+								Key:      "var3",
+								Value:    "[\"a\", \"b\"]",
+								Category: tfe.CategoryTerraform,
+								HCL:      true,
+							},
+							{
+								ID:       "var-5",
+								Key:      "VAR5",
+								Value:    "$HOME",
+								Category: tfe.CategoryEnv,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "",
+			wantErr:   true,
+			expectErr: "unknown format",
+		},
+		{
+			name:        "show variables with template format",
+			workspaceId: "w-test-variables-template-workspace",
+			showOpt:     &ShowOption{format: "template", template: `{{range .}}{{.Key}}={{mask .}} {{hcl .Value}} {{json .Description}} {{.Category}} {{.Source}}{{"\n"}}{{end}}`},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-template-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								ID:          "var-1",
+								Key:         "var1",
+								Value:       "it's \"quoted\"",
+								Description: "multi\nline",
+								Category:    tfe.CategoryTerraform,
+							},
+							{
+								ID:        "var-2",
+								Key:       "var2",
+								Value:     "secret",
+								Category:  tfe.CategoryTerraform,
+								Sensitive: true,
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "var1=it's \"quoted\" \"it's \\\"quoted\\\"\" \"multi\\nline\" terraform workspace\nvar2=(sensitive) \"secret\" \"\" terraform workspace\n",
+			wantErr:   false,
+			expectErr: "",
+		},
+		{
+			name:        "show variable set variables with template format",
+			workspaceId: "w-test-variables-template-varset-workspace",
+			showOpt:     &ShowOption{format: "template", includeVariableSet: true, template: `{{range .}}{{.Key}} {{.Source}} {{.VariableSet}}{{"\n"}}{{end}}`},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-template-varset-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "var1",
+								Value: "value1",
+							},
+						},
+					}, nil).
+					AnyTimes()
+				mvs.EXPECT().
+					ListForWorkspace(context.TODO(), "w-test-variables-template-varset-workspace", nil).
+					Return(&tfe.VariableSetList{
+						Items: []*tfe.VariableSet{
+							{
+								ID:   "variable-set-template-varset",
+								Name: "shared",
+							},
+						},
+					}, nil).
+					AnyTimes()
+				mvsv.EXPECT().
+					List(context.TODO(), "variable-set-template-varset", nil).
+					Return(&tfe.VariableSetVariableList{
+						Items: []*tfe.VariableSetVariable{
+							{
+								Key:   "var3",
+								Value: "value3",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "var1 workspace \nvar3 varset shared\n",
+			wantErr:   false,
+			expectErr: "",
+		},
+		{
+			name:        "return error with invalid template",
+			workspaceId: "w-test-variables-invalid-template-workspace",
+			showOpt:     &ShowOption{format: "template", template: "{{range .}}"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-invalid-template-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "var1",
+								Value: "value1",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "",
+			wantErr:   true,
+			expectErr: "invalid template",
+		},
 		{
 			name:        "show variables with table format",
 			workspaceId: "w-test-variables-table-workspace",
@@ -228,6 +688,170 @@ func TestCmdShow(t *testing.T) {
 | var4 |                          | true      |                  |
 | var6 | val6                     | false     | var6 description |
 +------+--------------------------+-----------+------------------+
+`,
+			wantErr:   false,
+			expectErr: "",
+		},
+		{
+			name:        "show variables sorted by category with table columns",
+			workspaceId: "w-test-variables-sorted-table-workspace",
+			showOpt:     &ShowOption{format: "table", includeEnv: true, sort: "category", columns: []string{"key", "category", "source"}},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-sorted-table-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:      "VAR2",
+								Value:    "value2",
+								Category: tfe.CategoryEnv,
+							},
+							{
+								Key:   "var3",
+								Value: "value3",
+							},
+							{
+								Key:      "VAR1",
+								Value:    "value1",
+								Category: tfe.CategoryEnv,
+							},
+							{
+								Key:   "var0",
+								Value: "value0",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: `+------+-----------+-----------+
+| KEY  | CATEGORY  |  SOURCE   |
++------+-----------+-----------+
+| VAR1 | env       | workspace |
+| VAR2 | env       | workspace |
+| var0 | terraform | workspace |
+| var3 | terraform | workspace |
++------+-----------+-----------+
+`,
+			wantErr:   false,
+			expectErr: "",
+		},
+		{
+			name:        "show only variable set variables filtered by attributes",
+			workspaceId: "w-test-variables-source-varset-workspace",
+			showOpt:     &ShowOption{format: "detail", source: SOURCE_VARSET, filter: &VariableFilter{regexps: []string{"^var[0-9]$"}, noSensitive: true}},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-source-varset-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "var1",
+								Value: "value1",
+							},
+						},
+					}, nil).
+					AnyTimes()
+				mvs.EXPECT().
+					ListForWorkspace(context.TODO(), "w-test-variables-source-varset-workspace", nil).
+					Return(&tfe.VariableSetList{
+						Items: []*tfe.VariableSet{
+							{
+								ID:   "variable-set-source-varset",
+								Name: "shared",
+							},
+						},
+					}, nil).
+					AnyTimes()
+				mvsv.EXPECT().
+					List(context.TODO(), "variable-set-source-varset", nil).
+					Return(&tfe.VariableSetVariableList{
+						Items: []*tfe.VariableSetVariable{
+							{
+								Key:   "var2",
+								Value: "value2",
+							},
+							{
+								Key:       "var3",
+								Sensitive: true,
+							},
+							{
+								Key:   "var_4",
+								Value: "value4",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "Key: var2\nValue: value2\nDescription: \nSensitive: false\n\n",
+			wantErr:   false,
+			expectErr: "",
+		},
+		{
+			name:        "return error with unknown table column",
+			workspaceId: "w-test-variables-unknown-column-workspace",
+			showOpt:     &ShowOption{format: "table", columns: []string{"key", "owner"}},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-unknown-column-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "var1",
+								Value: "value1",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect:    "",
+			wantErr:   true,
+			expectErr: "invalid column 'owner'",
+		},
+		{
+			name:        "redact secret-looking local variable",
+			workspaceId: "",
+			showOpt:     &ShowOption{local: true, varFile: "testdata/sensitive.tfvars", format: "detail"},
+			setClient:   func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {}, // do nothing
+			expect:      "Key: environment\nValue: test\nDescription: \nSensitive: false\n\nKey: db_password\nValue: (redacted)\nDescription: \nSensitive: false\n\n",
+			wantErr:     false,
+			expectErr:   "",
+		},
+		{
+			name:        "reveal secret-looking local variable",
+			workspaceId: "",
+			showOpt:     &ShowOption{local: true, varFile: "testdata/sensitive.tfvars", format: "detail", reveal: true},
+			setClient:   func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {}, // do nothing
+			expect:      "Key: environment\nValue: test\nDescription: \nSensitive: false\n\nKey: db_password\nValue: supersecret\nDescription: \nSensitive: false\n\n",
+			wantErr:     false,
+			expectErr:   "",
+		},
+		{
+			name:        "redact high entropy value in table format",
+			workspaceId: "w-test-variables-redact-table-workspace",
+			showOpt:     &ShowOption{format: "table"},
+			setClient: func(mc *mocks.MockVariables, mvs *mocks.MockVariableSets, mvsv *mocks.MockVariableSetVariables) {
+				mc.EXPECT().
+					List(context.TODO(), "w-test-variables-redact-table-workspace", nil).
+					Return(&tfe.VariableList{
+						Items: []*tfe.Variable{
+							{
+								Key:   "region",
+								Value: "ap-northeast-1",
+							},
+							{
+								Key:   "deploy_key",
+								Value: "AKIAx7Qp2Lm9Zr4Tw8Yb3Nc6",
+							},
+						},
+					}, nil).
+					AnyTimes()
+			},
+			expect: `+------------+----------------+-----------+-------------+
+|    KEY     |     VALUE      | SENSITIVE | DESCRIPTION |
++------------+----------------+-----------+-------------+
+| region     | ap-northeast-1 | false     |             |
+| deploy_key | (redacted)     | false     |             |
++------------+----------------+-----------+-------------+
 `,
 			wantErr:   false,
 			expectErr: "",
@@ -469,6 +1093,50 @@ tags = {
 	}
 }
 
+func TestShowLocalEncrypted(t *testing.T) {
+	identity := setupAgeIdentity(t)
+	encrypted, err := encryptAge([]byte(testTfvars), []string{identity.Recipient().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(t.TempDir(), "secret.tfvars.age")
+	if err := os.WriteFile(filename, encrypted, 0600); err != nil {
+		t.Fatal(err)
+	}
+	showOpt := &ShowOption{local: true, varFile: filename, format: "detail", reveal: true}
+	var buf bytes.Buffer
+
+	err = show(context.TODO(), "", nil, nil, nil, showOpt, &buf)
+
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	expect := "Key: environment\nValue: production\nDescription: \nSensitive: false\n\nKey: db_password\nValue: supersecret\nDescription: \nSensitive: false\n\n"
+	if buf.String() != expect {
+		t.Errorf("expect '%s', got '%s'", expect, buf.String())
+	}
+}
+
+func TestFormatType(t *testing.T) {
+	formatType := &FormatType{
+		Enum:    []string{"detail", "json"},
+		Default: "detail",
+	}
+
+	if formatType.String() != "detail" {
+		t.Errorf("expect default value 'detail', got '%s'", formatType.String())
+	}
+	if err := formatType.Set("xml"); err == nil || err.Error() != "invalid value 'xml', allowed values are detail, json" {
+		t.Errorf("expect invalid value error, got '%v'", err)
+	}
+	if err := formatType.Set("json"); err != nil {
+		t.Errorf("expect no error, got '%v'", err)
+	}
+	if formatType.String() != "json" {
+		t.Errorf("expect selected value 'json', got '%s'", formatType.String())
+	}
+}
+
 func TestNewShowOption(t *testing.T) {
 	cases := []struct {
 		name   string
@@ -479,36 +1147,55 @@ func TestNewShowOption(t *testing.T) {
 			name: "default value",
 			args: []string{},
 			expect: &ShowOption{
-				varFile: "terraform.tfvars",
-				local:   false,
-				format:  "detail",
+				varFile:   "terraform.tfvars",
+				local:     false,
+				format:    "detail",
+				sensitive: "comment",
 			},
 		},
 		{
 			name: "custom var file",
 			args: []string{"--var-file", "custom.tfvars"},
 			expect: &ShowOption{
-				varFile: "custom.tfvars",
-				local:   false,
-				format:  "detail",
+				varFile:   "custom.tfvars",
+				local:     false,
+				format:    "detail",
+				sensitive: "comment",
 			},
 		},
 		{
 			name: "enable local option",
 			args: []string{"--local"},
 			expect: &ShowOption{
-				varFile: "terraform.tfvars",
-				local:   true,
-				format:  "detail",
+				varFile:   "terraform.tfvars",
+				local:     true,
+				format:    "detail",
+				sensitive: "comment",
 			},
 		},
 		{
 			name: "specify variable",
 			args: []string{"--variable", "environment"},
 			expect: &ShowOption{
-				varFile:     "terraform.tfvars",
-				variableKey: "environment",
-				format:      "detail",
+				varFile:   "terraform.tfvars",
+				filter:    &VariableFilter{keys: []string{"environment"}},
+				format:    "detail",
+				sensitive: "comment",
+			},
+		},
+		{
+			name: "specify multiple variables and patterns",
+			args: []string{"--variable", "environment", "--variable", "region", "--include", "db_*", "--exclude", "db_password", "--category", "terraform"},
+			expect: &ShowOption{
+				varFile: "terraform.tfvars",
+				filter: &VariableFilter{
+					keys:     []string{"environment", "region"},
+					includes: []string{"db_*"},
+					excludes: []string{"db_password"},
+					category: "terraform",
+				},
+				format:    "detail",
+				sensitive: "comment",
 			},
 		},
 		{
@@ -518,6 +1205,7 @@ func TestNewShowOption(t *testing.T) {
 				varFile:    "terraform.tfvars",
 				includeEnv: true,
 				format:     "detail",
+				sensitive:  "comment",
 			},
 		},
 		{
@@ -527,6 +1215,31 @@ func TestNewShowOption(t *testing.T) {
 				varFile:            "terraform.tfvars",
 				includeVariableSet: true,
 				format:             "detail",
+				sensitive:          "comment",
+			},
+		},
+		{
+			name: "specify filters, sort and columns",
+			args: []string{"--regex", "^db_", "--no-sensitive", "--hcl-only", "--source", "varset", "--sort", "category", "--format", "table", "--columns", "key,value,category", "--reveal"},
+			expect: &ShowOption{
+				varFile:   "terraform.tfvars",
+				filter:    &VariableFilter{regexps: []string{"^db_"}, noSensitive: true, hclOnly: true},
+				format:    "table",
+				sensitive: "comment",
+				source:    "varset",
+				sort:      "category",
+				columns:   []string{"key", "value", "category"},
+				reveal:    true,
+			},
+		},
+		{
+			name: "specify template",
+			args: []string{"--format", "template", "--template", "{{range .}}{{.Key}}{{end}}"},
+			expect: &ShowOption{
+				varFile:   "terraform.tfvars",
+				format:    "template",
+				sensitive: "comment",
+				template:  "{{range .}}{{.Key}}{{end}}",
 			},
 		},
 	}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+type UndoOption struct {
+	id          int
+	dryRun      bool
+	autoApprove bool
+	reveal      bool
+	in          io.Reader
+	out         io.Writer
+}
+
+func NewUndoOption(c *cli.Context) *UndoOption {
+	var opt = &UndoOption{}
+
+	opt.id = c.Int("id")
+	opt.dryRun = c.Bool("dry-run")
+	opt.autoApprove = c.Bool("auto-approve")
+	opt.reveal = c.Bool("reveal")
+
+	opt.in = os.Stdin
+	opt.out = os.Stdout
+
+	return opt
+}
+
+func Undo(c *cli.Context) error {
+	ctx := context.Background()
+	log.Debug().Msg("undo command")
+
+	undoOpt := NewUndoOption(c)
+	if changeJournal == nil {
+		return errors.New("change journal is not available")
+	}
+	entry, err := lookupUndoEntry(changeJournal, undoOpt.id)
+	if err != nil {
+		return err
+	}
+
+	tfeClient, err := NewTfeClient(c)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build tfe client")
+		return err
+	}
+
+	switch entry.Target {
+	case JOURNAL_TARGET_WORKSPACE:
+		return undoWorkspace(ctx, entry, tfeClient.Variables, undoOpt)
+	case JOURNAL_TARGET_VARIABLE_SET:
+		return undoVariableSet(ctx, entry, tfeClient.VariableSetVariables, undoOpt)
+	}
+
+	return fmt.Errorf("unknown target '%s' of change %d", entry.Target, entry.ID)
+}
+
+// lookupUndoEntry return entry of id, or the latest entry if id is not specified
+func lookupUndoEntry(journal *Journal, id int) (*JournalEntry, error) {
+	if id != 0 {
+		return journal.entry(id)
+	}
+
+	entries, err := journal.entries()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("no changes recorded in journal")
+	}
+
+	return entries[len(entries)-1], nil
+}
+
+// undoWorkspace revert changes of entry applied to workspace
+func undoWorkspace(ctx context.Context, entry *JournalEntry, tfeVariables tfe.Variables, undoOpt *UndoOption) error {
+	variables, err := tfeVariables.List(ctx, entry.TargetID, nil)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to list variables of workspace %s", entry.TargetID)
+		return err
+	}
+
+	return applyUndo(entry, variables.Items, func(planned []*PushVariable) error {
+		return applyPushVariables(ctx, entry.TargetID, tfeVariables, planned, undoOpt.out)
+	}, undoOpt)
+}
+
+// undoVariableSet revert changes of entry applied to variable set
+func undoVariableSet(ctx context.Context, entry *JournalEntry, tfeVariableSetVariables tfe.VariableSetVariables, undoOpt *UndoOption) error {
+	variableList, err := tfeVariableSetVariables.List(ctx, entry.TargetID, nil)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to list VariableSetVariables ID: %s", entry.TargetID)
+		return err
+	}
+	variables := []*tfe.Variable{}
+	for _, v := range variableList.Items {
+		variables = append(variables, convertVariableSetVariable(v))
+	}
+
+	return applyUndo(entry, variables, func(planned []*PushVariable) error {
+		return applyVariableSetPushVariables(ctx, entry.TargetID, tfeVariableSetVariables, planned, undoOpt.out)
+	}, undoOpt)
+}
+
+// applyUndo print inverse operations of entry, confirm and apply them
+func applyUndo(entry *JournalEntry, currentVars []*tfe.Variable, apply func([]*PushVariable) error, undoOpt *UndoOption) error {
+	plan := planUndo(entry, currentVars)
+
+	fmt.Fprintf(undoOpt.out, "undo #%d %s %s %s:\n", entry.ID, entry.Command, entry.Target, entry.TargetID)
+	plan.write(undoOpt.out, undoOpt.reveal)
+	if len(plan.variables) == 0 || undoOpt.dryRun {
+		return nil
+	}
+
+	if !undoOpt.autoApprove {
+		if err := requireTerminal(undoOpt.in); err != nil {
+			return err
+		}
+		fmt.Fprint(undoOpt.out, "\nAre you sure you want to undo changes in Terraform Cloud? [y/n]: ")
+		res, err := confirm(undoOpt.in)
+		if err != nil {
+			return err
+		}
+		if !res {
+			return nil
+		}
+	}
+
+	return apply(plan.variables)
+}
+
+// planUndo return inverse operations of entry in reverse order
+// variables are looked up by key and category since IDs change when deleted and created again,
+// and values of sensitive variables are not recorded, so they are restored with placeholder or left as current
+func planUndo(entry *JournalEntry, currentVars []*tfe.Variable) *copyPlan {
+	plan := &copyPlan{
+		variables:   []*PushVariable{},
+		skipped:     []string{},
+		placeholder: []string{},
+		kept:        []string{},
+	}
+
+	for i := len(entry.Operations) - 1; i >= 0; i-- {
+		op := entry.Operations[i]
+		switch op.Operation {
+		case PUSH_OPERATION_CREATE:
+			current := findVariable(currentVars, op.After.variable(op.Key))
+			if current == nil {
+				plan.skipped = append(plan.skipped, op.Key+": already deleted")
+				continue
+			}
+			plan.variables = append(plan.variables, &PushVariable{
+				operation: PUSH_OPERATION_DELETE,
+				id:        current.ID,
+				previous:  current,
+			})
+		case PUSH_OPERATION_DELETE:
+			before := op.Before.variable(op.Key)
+			if findVariable(currentVars, before) != nil {
+				plan.skipped = append(plan.skipped, op.Key+": already exists")
+				continue
+			}
+			if op.Before.Value == nil {
+				before.Value = copyPlaceholder(before)
+				plan.placeholder = append(plan.placeholder, op.Key)
+			}
+			plan.variables = append(plan.variables, &PushVariable{
+				operation: PUSH_OPERATION_CREATE,
+				createOption: tfe.VariableCreateOptions{
+					Key:         tfe.String(before.Key),
+					Value:       tfe.String(before.Value),
+					Description: tfe.String(before.Description),
+					Category:    tfe.Category(before.Category),
+					HCL:         tfe.Bool(before.HCL),
+					Sensitive:   tfe.Bool(before.Sensitive),
+				},
+			})
+		case PUSH_OPERATION_UPDATE:
+			current := findVariable(currentVars, op.After.variable(op.Key))
+			if current == nil {
+				plan.skipped = append(plan.skipped, op.Key+": not found")
+				continue
+			}
+			if current.Sensitive && !op.Before.Sensitive {
+				// Terraform Cloud rejects to turn sensitive variable into non-sensitive
+				plan.skipped = append(plan.skipped, op.Key+": sensitive variable cannot be made non-sensitive, delete and create it again")
+				continue
+			}
+			updateOption := tfe.VariableUpdateOptions{
+				Key:         tfe.String(op.Key),
+				Description: tfe.String(op.Before.Description),
+				Category:    tfe.Category(op.Before.Category),
+				HCL:         tfe.Bool(op.Before.HCL),
+				Sensitive:   tfe.Bool(op.Before.Sensitive),
+			}
+			if op.Before.Value != nil {
+				updateOption.Value = tfe.String(*op.Before.Value)
+			}
+			variable := &PushVariable{
+				operation:    PUSH_OPERATION_UPDATE,
+				id:           current.ID,
+				previous:     current,
+				updateOption: updateOption,
+			}
+			if op.Before.Value == nil {
+				if len(variable.metadataChanges()) == 0 {
+					plan.skipped = append(plan.skipped, op.Key+": value of sensitive variable cannot be restored")
+					continue
+				}
+				plan.kept = append(plan.kept, op.Key)
+			}
+			plan.variables = append(plan.variables, variable)
+		}
+	}
+
+	return plan
+}
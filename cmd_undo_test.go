@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-tfe/mocks"
+	"github.com/urfave/cli/v2"
+)
+
+func TestPlanUndo(t *testing.T) {
+	entry := &JournalEntry{
+		ID:       3,
+		Command:  "push",
+		Target:   JOURNAL_TARGET_WORKSPACE,
+		TargetID: "ws-test",
+		Operations: []*JournalOperation{
+			{Operation: PUSH_OPERATION_CREATE, Key: "zones", VariableID: "v-zones", After: &JournalVariable{Category: tfe.CategoryTerraform, HCL: true}},
+			{
+				Operation:  PUSH_OPERATION_UPDATE,
+				Key:        "environment",
+				VariableID: "v-environment",
+				Before:     &JournalVariable{Value: tfe.String("staging"), Category: tfe.CategoryTerraform},
+				After:      &JournalVariable{Description: "env name", Category: tfe.CategoryTerraform},
+			},
+			{Operation: PUSH_OPERATION_DELETE, Key: "port", Before: &JournalVariable{Value: tfe.String("8080"), Category: tfe.CategoryTerraform}},
+			{Operation: PUSH_OPERATION_DELETE, Key: "db_password", Before: &JournalVariable{Category: tfe.CategoryTerraform, Sensitive: true}},
+		},
+	}
+
+	cases := []struct {
+		name        string
+		currentVars []*tfe.Variable
+		expect      string
+	}{
+		{
+			name: "revert all operations in reverse order",
+			currentVars: []*tfe.Variable{
+				{ID: "v-zones", Key: "zones", Value: `["a"]`, Category: tfe.CategoryTerraform, HCL: true},
+				{ID: "v-environment", Key: "environment", Value: "production", Description: "env name", Category: tfe.CategoryTerraform},
+			},
+			expect: `create db_password = "(sensitive)"
+create port = "8080"
+update environment: "production" -> "staging"
+~ environment: description "env name" -> ""
+delete zones
+Plan: 2 to create, 1 to update, 1 to delete, 0 skipped.
+
+Sensitive variables are created with placeholder values, set their values afterwards:
+  db_password
+`,
+		},
+		{
+			name: "skip operations already reverted",
+			currentVars: []*tfe.Variable{
+				{ID: "v-port", Key: "port", Value: "8080", Category: tfe.CategoryTerraform},
+				{ID: "v-db_password", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+			},
+			expect: `skip db_password: already exists
+skip port: already exists
+skip environment: not found
+skip zones: already deleted
+Plan: 0 to create, 0 to update, 0 to delete, 4 skipped.
+`,
+		},
+		{
+			name: "skip update which made variable sensitive",
+			currentVars: []*tfe.Variable{
+				{ID: "v-port", Key: "port", Value: "8080", Category: tfe.CategoryTerraform},
+				{ID: "v-db_password", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+				{ID: "v-environment", Key: "environment", Description: "env name", Category: tfe.CategoryTerraform, Sensitive: true},
+			},
+			expect: `skip db_password: already exists
+skip port: already exists
+skip environment: sensitive variable cannot be made non-sensitive, delete and create it again
+skip zones: already deleted
+Plan: 0 to create, 0 to update, 0 to delete, 4 skipped.
+`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planUndo(entry, tt.currentVars)
+
+			outBuf := new(bytes.Buffer)
+			plan.write(outBuf, false)
+			if outBuf.String() != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, outBuf.String())
+			}
+		})
+	}
+}
+
+func TestUndoWorkspace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVariables := mocks.NewMockVariables(ctrl)
+
+	entry := &JournalEntry{
+		ID:       1,
+		Command:  "push",
+		Target:   JOURNAL_TARGET_WORKSPACE,
+		TargetID: "ws-test",
+		Operations: []*JournalOperation{
+			{
+				Operation: PUSH_OPERATION_UPDATE,
+				Key:       "db_password",
+				Before:    &JournalVariable{Description: "database password", Category: tfe.CategoryTerraform, Sensitive: true},
+				After:     &JournalVariable{Category: tfe.CategoryTerraform, Sensitive: true},
+			},
+			{
+				Operation: PUSH_OPERATION_UPDATE,
+				Key:       "api_token",
+				Before:    &JournalVariable{Category: tfe.CategoryTerraform, Sensitive: true},
+				After:     &JournalVariable{Category: tfe.CategoryTerraform, Sensitive: true},
+			},
+			{Operation: PUSH_OPERATION_CREATE, Key: "zones", After: &JournalVariable{Category: tfe.CategoryTerraform, HCL: true}},
+		},
+	}
+	currentVars := []*tfe.Variable{
+		{ID: "v-db_password", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+		{ID: "v-api_token", Key: "api_token", Category: tfe.CategoryTerraform, Sensitive: true},
+		{ID: "v-zones", Key: "zones", Value: `["a"]`, Category: tfe.CategoryTerraform, HCL: true},
+	}
+
+	cases := []struct {
+		name      string
+		undoOpt   *UndoOption
+		setClient func(*mocks.MockVariables)
+		input     string
+		expect    string
+	}{
+		{
+			name:    "revert changes and keep value of sensitive variable",
+			undoOpt: &UndoOption{autoApprove: true},
+			setClient: func(mc *mocks.MockVariables) {
+				mc.EXPECT().Delete(gomock.Any(), "ws-test", "v-zones").Return(nil)
+				mc.EXPECT().Update(gomock.Any(), "ws-test", "v-db_password", tfe.VariableUpdateOptions{
+					Key:         tfe.String("db_password"),
+					Description: tfe.String("database password"),
+					Category:    tfe.Category(tfe.CategoryTerraform),
+					HCL:         tfe.Bool(false),
+					Sensitive:   tfe.Bool(true),
+				}).Return(&tfe.Variable{}, nil)
+			},
+			expect: "undo #1 push workspace ws-test:\ndelete zones\nupdate db_password: \"(sensitive)\" -> \"(sensitive)\"\n~ db_password: description \"\" -> \"database password\"\nskip api_token: value of sensitive variable cannot be restored\nPlan: 0 to create, 1 to update, 1 to delete, 1 skipped.\n\nSensitive variables keep their current values since their values are not available:\n  db_password\n",
+		},
+		{
+			name:      "print plan without reverting in dry-run mode",
+			undoOpt:   &UndoOption{dryRun: true},
+			setClient: func(mc *mocks.MockVariables) {},
+			expect:    "undo #1 push workspace ws-test:\ndelete zones\nupdate db_password: \"(sensitive)\" -> \"(sensitive)\"\n~ db_password: description \"\" -> \"database password\"\nskip api_token: value of sensitive variable cannot be restored\nPlan: 0 to create, 1 to update, 1 to delete, 1 skipped.\n\nSensitive variables keep their current values since their values are not available:\n  db_password\n",
+		},
+		{
+			name:      "do nothing if approve declined",
+			undoOpt:   &UndoOption{},
+			setClient: func(mc *mocks.MockVariables) {},
+			input:     "n\n",
+			expect:    "undo #1 push workspace ws-test:\ndelete zones\nupdate db_password: \"(sensitive)\" -> \"(sensitive)\"\n~ db_password: description \"\" -> \"database password\"\nskip api_token: value of sensitive variable cannot be restored\nPlan: 0 to create, 1 to update, 1 to delete, 1 skipped.\n\nSensitive variables keep their current values since their values are not available:\n  db_password\n\nAre you sure you want to undo changes in Terraform Cloud? [y/n]: ",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVariables.EXPECT().List(gomock.Any(), "ws-test", nil).Return(&tfe.VariableList{Items: currentVars}, nil)
+			tt.setClient(mockVariables)
+			tt.undoOpt.in = strings.NewReader(tt.input)
+			outBuf := new(bytes.Buffer)
+			tt.undoOpt.out = outBuf
+
+			err := undoWorkspace(context.TODO(), entry, mockVariables, tt.undoOpt)
+
+			if err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+			if outBuf.String() != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, outBuf.String())
+			}
+		})
+	}
+}
+
+func TestLookupUndoEntry(t *testing.T) {
+	journal := NewJournal(filepath.Join(t.TempDir(), "journal.jsonl"), "push")
+
+	if _, err := lookupUndoEntry(journal, 0); err == nil {
+		t.Errorf("expect error for empty journal, got no error")
+	}
+
+	operations := []*JournalOperation{{Operation: PUSH_OPERATION_DELETE, Key: "port"}}
+	for _, id := range []string{"ws-first", "ws-second"} {
+		if err := journal.record(JOURNAL_TARGET_WORKSPACE, id, operations); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	latest, err := lookupUndoEntry(journal, 0)
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	if latest.TargetID != "ws-second" {
+		t.Errorf("expect latest entry, got '%+v'", latest)
+	}
+	first, err := lookupUndoEntry(journal, 1)
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	if first.TargetID != "ws-first" {
+		t.Errorf("expect entry 1, got '%+v'", first)
+	}
+}
+
+func TestNewUndoOption(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		expect *UndoOption
+	}{
+		{
+			name:   "default value",
+			args:   []string{},
+			expect: &UndoOption{in: os.Stdin, out: os.Stdout},
+		},
+		{
+			name:   "specify all options",
+			args:   []string{"--id", "3", "--dry-run", "--auto-approve"},
+			expect: &UndoOption{id: 3, dryRun: true, autoApprove: true, in: os.Stdin, out: os.Stdout},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			app := cli.NewApp()
+			set := flagSet(undoFlags())
+			set.Parse(tt.args)
+			ctx := cli.NewContext(app, set, nil)
+
+			sut := NewUndoOption(ctx)
+
+			if !reflect.DeepEqual(tt.expect, sut) {
+				t.Errorf("expect '%+v', got '%+v'", tt.expect, sut)
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	WATCH_EVENT_DRIFT    = "drift"
+	WATCH_EVENT_RESOLVED = "resolved"
+)
+
+// webhookTimeout is timeout to post an event to webhook
+const webhookTimeout = 10 * time.Second
+
+type WatchOption struct {
+	targets   []string
+	varFile   string
+	interval  time.Duration
+	format    string
+	webhook   string
+	stateFile string
+	once      bool
+	diffOpt   *DiffOption
+	client    *http.Client
+	out       io.Writer
+}
+
+func NewWatchOption(c *cli.Context) *WatchOption {
+	var opt = &WatchOption{}
+
+	opt.targets = c.StringSlice("target")
+	opt.varFile = c.String("var-file")
+	opt.interval = c.Duration("interval")
+	opt.format = c.String("format")
+	opt.webhook = c.String("webhook")
+	opt.stateFile = c.String("state-file")
+	opt.once = c.Bool("once")
+	opt.diffOpt = &DiffOption{
+		includeVariableSet: c.Bool("include-variable-set"),
+		filter:             NewVariableFilter(c, c.StringSlice("variable")),
+		syncDescriptions:   c.Bool("sync-descriptions"),
+	}
+
+	opt.client = &http.Client{Timeout: webhookTimeout}
+	opt.out = os.Stdout
+
+	return opt
+}
+
+// WatchTarget is a workspace compared with local var-file
+type WatchTarget struct {
+	organization string
+	workspace    string
+	varFile      string
+}
+
+// NewWatchTarget parse ORG/WORKSPACE=FILE, or ORG/WORKSPACE compared with default var-file
+func NewWatchTarget(spec string, defaultVarFile string) (*WatchTarget, error) {
+	workspace, varFile, found := strings.Cut(spec, "=")
+	if !found || varFile == "" {
+		varFile = defaultVarFile
+	}
+	org, ws, err := parseWorkspaceSpec(workspace)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target '%s': specify ORG/WORKSPACE=FILE", spec)
+	}
+
+	return &WatchTarget{organization: org, workspace: ws, varFile: varFile}, nil
+}
+
+func (t *WatchTarget) String() string {
+	return t.organization + "/" + t.workspace + "=" + t.varFile
+}
+
+// WatchEvent is drift newly found or resolved in a target
+type WatchEvent struct {
+	Time      time.Time    `json:"time"`
+	Event     string       `json:"event"`
+	Workspace string       `json:"workspace"`
+	VarFile   string       `json:"var_file"`
+	Entries   []*DiffEntry `json:"entries"`
+}
+
+// WatchState is fingerprints of drifted variables by target, to report only new drift
+type WatchState map[string]map[string]string
+
+func Watch(c *cli.Context) error {
+	log.Debug().Msg("watch command")
+
+	watchOpt := NewWatchOption(c)
+	if err := watchOpt.diffOpt.filter.Validate(); err != nil {
+		return err
+	}
+	if watchOpt.interval <= 0 {
+		return errors.New("--interval must be positive")
+	}
+	targets := []*WatchTarget{}
+	for _, spec := range watchOpt.targets {
+		target, err := NewWatchTarget(spec, watchOpt.varFile)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, target)
+	}
+	if len(targets) == 0 {
+		org, ws := updateTerraformCloudWorkspace(organization, workspaceName, ".")
+		targets = append(targets, &WatchTarget{organization: org, workspace: ws, varFile: watchOpt.varFile})
+	}
+
+	tfeClient, err := NewTfeClient(c)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build tfe client")
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return watch(ctx, targets, tfeClient.Workspaces, tfeClient.Variables, tfeClient.VariableSets, tfeClient.VariableSetVariables, watchOpt)
+}
+
+// watch check drift of targets every interval until context is canceled
+func watch(ctx context.Context, targets []*WatchTarget, tfeWorkspaces tfe.Workspaces, tfeVariables tfe.Variables, tfeVariableSets tfe.VariableSets, tfeVariableSetVariables tfe.VariableSetVariables, watchOpt *WatchOption) error {
+	for {
+		err := watchOnce(ctx, targets, tfeWorkspaces, tfeVariables, tfeVariableSets, tfeVariableSetVariables, watchOpt)
+		if watchOpt.once {
+			return err
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("failed to check drift")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchOpt.interval):
+		}
+	}
+}
+
+// watchOnce check drift of every target, report changes since the last check and save state
+// failure of a target does not prevent others from being checked
+func watchOnce(ctx context.Context, targets []*WatchTarget, tfeWorkspaces tfe.Workspaces, tfeVariables tfe.Variables, tfeVariableSets tfe.VariableSets, tfeVariableSetVariables tfe.VariableSetVariables, watchOpt *WatchOption) error {
+	state, err := loadWatchState(watchOpt.stateFile)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, target := range targets {
+		entries, err := checkDrift(ctx, target, tfeWorkspaces, tfeVariables, tfeVariableSets, tfeVariableSetVariables, watchOpt.diffOpt)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", target, err))
+			continue
+		}
+		previous, reported := state[target.String()]
+		emitted := true
+		for _, event := range state.update(target, entries, time.Now().UTC()) {
+			if err := emitWatchEvent(ctx, event, watchOpt); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", target, err))
+				emitted = false
+			}
+		}
+		// keep previous state to report again at next check
+		if !emitted && reported {
+			state[target.String()] = previous
+		} else if !emitted {
+			delete(state, target.String())
+		}
+	}
+
+	if err := state.save(watchOpt.stateFile); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkDrift return entries of variables different between workspace and var-file of target
+func checkDrift(ctx context.Context, target *WatchTarget, tfeWorkspaces tfe.Workspaces, tfeVariables tfe.Variables, tfeVariableSets tfe.VariableSets, tfeVariableSetVariables tfe.VariableSetVariables, diffOpt *DiffOption) ([]*DiffEntry, error) {
+	from, err := loadDiffSide(ctx, &DiffSource{organization: target.organization, workspace: target.workspace}, tfeWorkspaces, tfeVariables, tfeVariableSets, tfeVariableSetVariables, diffOpt)
+	if err != nil {
+		return nil, err
+	}
+	to, err := newLocalDiffSide(target.varFile, diffOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := []*DiffEntry{}
+	for _, entry := range compareDiffSides(from, to, diffOpt).entries {
+		if entry.Change != DIFF_UNCHANGED {
+			drift = append(drift, entry)
+		}
+	}
+
+	return drift, nil
+}
+
+// update replace fingerprints of target with current drift
+// return events of drift not reported before and drift which no longer exists
+func (state WatchState) update(target *WatchTarget, entries []*DiffEntry, now time.Time) []*WatchEvent {
+	previous := state[target.String()]
+	current := map[string]string{}
+	drift := []*DiffEntry{}
+	for _, entry := range entries {
+		current[entry.Key] = fingerprintDiffEntry(entry)
+		if previous[entry.Key] != current[entry.Key] {
+			drift = append(drift, entry)
+		}
+	}
+
+	resolvedKeys := []string{}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			resolvedKeys = append(resolvedKeys, key)
+		}
+	}
+	sort.Strings(resolvedKeys)
+	resolved := []*DiffEntry{}
+	for _, key := range resolvedKeys {
+		resolved = append(resolved, &DiffEntry{Key: key, Change: DIFF_UNCHANGED})
+	}
+
+	if len(current) == 0 {
+		delete(state, target.String())
+	} else {
+		state[target.String()] = current
+	}
+
+	events := []*WatchEvent{}
+	if len(drift) != 0 {
+		events = append(events, target.event(WATCH_EVENT_DRIFT, drift, now))
+	}
+	if len(resolved) != 0 {
+		events = append(events, target.event(WATCH_EVENT_RESOLVED, resolved, now))
+	}
+
+	return events
+}
+
+func (t *WatchTarget) event(event string, entries []*DiffEntry, now time.Time) *WatchEvent {
+	return &WatchEvent{
+		Time:      now,
+		Event:     event,
+		Workspace: t.organization + "/" + t.workspace,
+		VarFile:   t.varFile,
+		Entries:   entries,
+	}
+}
+
+// fingerprintDiffEntry return hash of entry, so that state file does not keep values
+func fingerprintDiffEntry(entry *DiffEntry) string {
+	data, _ := json.Marshal(entry)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// loadWatchState read state file, or return empty state if it does not exist
+func loadWatchState(path string) (WatchState, error) {
+	state := WatchState{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Msgf("cannot read state file: %s", path)
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+
+	return state, nil
+}
+
+func (state WatchState) save(path string) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, append(data, '\n'), false)
+}
+
+// emitWatchEvent print event and post it to webhook if configured
+func emitWatchEvent(ctx context.Context, event *WatchEvent, watchOpt *WatchOption) error {
+	if watchOpt.format == "json" {
+		if err := json.NewEncoder(watchOpt.out).Encode(event); err != nil {
+			return err
+		}
+	} else {
+		writeWatchEvent(watchOpt.out, event)
+	}
+
+	if watchOpt.webhook == "" {
+		return nil
+	}
+
+	return postWatchEvent(ctx, watchOpt.client, watchOpt.webhook, event)
+}
+
+// writeWatchEvent print event as text
+func writeWatchEvent(w io.Writer, event *WatchEvent) {
+	fmt.Fprintf(w, "%s %s in %s (%s):\n", event.Time.Format(time.RFC3339), event.Event, event.Workspace, event.VarFile)
+	for _, entry := range event.Entries {
+		switch entry.Change {
+		case DIFF_ADDED:
+			fmt.Fprintf(w, "  %s: only in local\n", entry.Key)
+		case DIFF_REMOVED:
+			fmt.Fprintf(w, "  %s: only in remote\n", entry.Key)
+		case DIFF_UNCHANGED:
+			fmt.Fprintf(w, "  %s\n", entry.Key)
+		default:
+			fmt.Fprintf(w, "  %s: %s\n", entry.Key, entry.Change)
+		}
+		for _, change := range entry.Metadata {
+			fmt.Fprintf(w, "  %s\n", formatMetadataChange(entry.Key, change))
+		}
+	}
+}
+
+// postWatchEvent send event to webhook as JSON
+func postWatchEvent(ctx context.Context, client *http.Client, url string, event *WatchEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to post event to webhook")
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %s", res.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-tfe/mocks"
+	"github.com/urfave/cli/v2"
+)
+
+func TestNewWatchTarget(t *testing.T) {
+	cases := []struct {
+		name    string
+		spec    string
+		expect  *WatchTarget
+		wantErr bool
+	}{
+		{
+			name:   "workspace with var-file",
+			spec:   "org/production=envs/production.tfvars",
+			expect: &WatchTarget{organization: "org", workspace: "production", varFile: "envs/production.tfvars"},
+		},
+		{
+			name:   "workspace with default var-file",
+			spec:   "org/production",
+			expect: &WatchTarget{organization: "org", workspace: "production", varFile: "terraform.tfvars"},
+		},
+		{
+			name:    "invalid workspace",
+			spec:    "production=production.tfvars",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := NewWatchTarget(tt.spec, "terraform.tfvars")
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expect error, got no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+			if !reflect.DeepEqual(tt.expect, target) {
+				t.Errorf("expect '%+v', got '%+v'", tt.expect, target)
+			}
+		})
+	}
+}
+
+func TestWatchOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockWorkspaces := mocks.NewMockWorkspaces(ctrl)
+	mockVariables := mocks.NewMockVariables(ctrl)
+
+	received := []*WatchEvent{}
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		event := &WatchEvent{}
+		if err := json.NewDecoder(r.Body).Decode(event); err != nil {
+			t.Errorf("invalid event: %v", err)
+		}
+		received = append(received, event)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	varFile := filepath.Join(dir, "terraform.tfvars")
+	if err := os.WriteFile(varFile, []byte("environment = \"production\"\nregion = \"ap-northeast-1\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	targets := []*WatchTarget{{organization: "org", workspace: "production", varFile: varFile}}
+
+	cases := []struct {
+		name       string
+		remoteVars []*tfe.Variable
+		status     int
+		wantErr    bool
+		expect     []string
+	}{
+		{
+			name: "report drift found first",
+			remoteVars: []*tfe.Variable{
+				{ID: "v-environment", Key: "environment", Value: "staging", Category: tfe.CategoryTerraform},
+				{ID: "v-region", Key: "region", Value: "ap-northeast-1", Category: tfe.CategoryTerraform},
+			},
+			expect: []string{"drift:environment"},
+		},
+		{
+			name: "do not report the same drift again",
+			remoteVars: []*tfe.Variable{
+				{ID: "v-environment", Key: "environment", Value: "staging", Category: tfe.CategoryTerraform},
+				{ID: "v-region", Key: "region", Value: "ap-northeast-1", Category: tfe.CategoryTerraform},
+			},
+			expect: []string{},
+		},
+		{
+			name: "report new drift only",
+			remoteVars: []*tfe.Variable{
+				{ID: "v-environment", Key: "environment", Value: "staging", Category: tfe.CategoryTerraform},
+				{ID: "v-region", Key: "region", Value: "us-east-1", Category: tfe.CategoryTerraform},
+				{ID: "v-debug", Key: "debug", Value: "true", Category: tfe.CategoryTerraform},
+			},
+			expect: []string{"drift:debug,region"},
+		},
+		{
+			name: "report again at next check if webhook fails",
+			remoteVars: []*tfe.Variable{
+				{ID: "v-environment", Key: "environment", Value: "production", Category: tfe.CategoryTerraform},
+				{ID: "v-region", Key: "region", Value: "us-east-1", Category: tfe.CategoryTerraform},
+				{ID: "v-debug", Key: "debug", Value: "true", Category: tfe.CategoryTerraform},
+			},
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+			expect:  []string{"resolved:environment"},
+		},
+		{
+			name: "report resolved drift",
+			remoteVars: []*tfe.Variable{
+				{ID: "v-environment", Key: "environment", Value: "production", Category: tfe.CategoryTerraform},
+				{ID: "v-region", Key: "region", Value: "ap-northeast-1", Category: tfe.CategoryTerraform},
+			},
+			expect: []string{"resolved:debug,environment,region"},
+		},
+	}
+
+	watchOpt := &WatchOption{
+		format:    "json",
+		webhook:   server.URL,
+		stateFile: filepath.Join(dir, "state.json"),
+		once:      true,
+		diffOpt:   &DiffOption{},
+		client:    server.Client(),
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			received = []*WatchEvent{}
+			status = http.StatusOK
+			if tt.status != 0 {
+				status = tt.status
+			}
+			mockWorkspaces.EXPECT().Read(gomock.Any(), "org", "production").Return(&tfe.Workspace{ID: "ws-production"}, nil)
+			mockVariables.EXPECT().List(gomock.Any(), "ws-production", nil).Return(&tfe.VariableList{Items: tt.remoteVars}, nil)
+			outBuf := new(bytes.Buffer)
+			watchOpt.out = outBuf
+
+			err := watch(context.TODO(), targets, mockWorkspaces, mockVariables, nil, nil, watchOpt)
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expect error, got no error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+			got := []string{}
+			for _, event := range received {
+				keys := []string{}
+				for _, entry := range event.Entries {
+					keys = append(keys, entry.Key)
+				}
+				got = append(got, event.Event+":"+strings.Join(keys, ","))
+				if event.Workspace != "org/production" || event.VarFile != varFile {
+					t.Errorf("unexpected target of event '%+v'", event)
+				}
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Errorf("expect '%v', got '%v'", tt.expect, got)
+			}
+			if lines := strings.Count(outBuf.String(), "\n"); lines != len(tt.expect) {
+				t.Errorf("expect %d events printed, got '%s'", len(tt.expect), outBuf.String())
+			}
+		})
+	}
+}
+
+func TestWriteWatchEvent(t *testing.T) {
+	event := &WatchEvent{
+		Time:      time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Event:     WATCH_EVENT_DRIFT,
+		Workspace: "org/production",
+		VarFile:   "terraform.tfvars",
+		Entries: []*DiffEntry{
+			{Key: "debug", Change: DIFF_REMOVED},
+			{Key: "environment", Change: DIFF_CHANGED, Metadata: []MetadataChange{{Attribute: "hcl", Old: "false", New: "true"}}},
+			{Key: "region", Change: DIFF_ADDED},
+		},
+	}
+	expect := `2024-01-02T03:04:05Z drift in org/production (terraform.tfvars):
+  debug: only in remote
+  environment: changed
+  ~ environment: hcl false -> true
+  region: only in local
+`
+
+	outBuf := new(bytes.Buffer)
+	writeWatchEvent(outBuf, event)
+
+	if outBuf.String() != expect {
+		t.Errorf("expect '%s', got '%s'", expect, outBuf.String())
+	}
+}
+
+func TestNewWatchOption(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		expect *WatchOption
+	}{
+		{
+			name: "default value",
+			args: []string{},
+			expect: &WatchOption{
+				varFile:   "terraform.tfvars",
+				interval:  5 * time.Minute,
+				format:    "text",
+				stateFile: ".tfcvars-watch-state.json",
+				diffOpt:   &DiffOption{},
+				client:    &http.Client{Timeout: webhookTimeout},
+				out:       os.Stdout,
+			},
+		},
+		{
+			name: "specify all options",
+			args: []string{"--target", "org/production=production.tfvars", "--target", "org/staging", "--var-file", "default.tfvars", "--interval", "30s", "--once", "--format", "json", "--webhook", "http://localhost:8080/hook", "--state-file", "state.json", "--include-variable-set", "--variable", "environment", "--sync-descriptions"},
+			expect: &WatchOption{
+				targets:   []string{"org/production=production.tfvars", "org/staging"},
+				varFile:   "default.tfvars",
+				interval:  30 * time.Second,
+				format:    "json",
+				webhook:   "http://localhost:8080/hook",
+				stateFile: "state.json",
+				once:      true,
+				diffOpt: &DiffOption{
+					includeVariableSet: true,
+					filter:             &VariableFilter{keys: []string{"environment"}},
+					syncDescriptions:   true,
+				},
+				client: &http.Client{Timeout: webhookTimeout},
+				out:    os.Stdout,
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			app := cli.NewApp()
+			set := flagSet(watchFlags())
+			set.Parse(tt.args)
+			ctx := cli.NewContext(app, set, nil)
+
+			sut := NewWatchOption(ctx)
+
+			if !reflect.DeepEqual(tt.expect, sut) {
+				t.Errorf("expect '%+v', got '%+v'", tt.expect, sut)
+			}
+		})
+	}
+}
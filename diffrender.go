@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+const (
+	COLOR_AUTO   = "auto"
+	COLOR_ALWAYS = "always"
+	COLOR_NEVER  = "never"
+)
+
+const (
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorCyan   = "\x1b[36m"
+	colorReset  = "\x1b[0m"
+)
+
+// DiffRenderOption specify how to print line based difference
+type DiffRenderOption struct {
+	color   bool
+	context int // number of unchanged lines around changes, all lines if negative
+	unified bool
+}
+
+// useColor decide whether to colorize output written to w
+// NO_COLOR disables color only in auto mode
+func useColor(mode string, w io.Writer) bool {
+	switch mode {
+	case COLOR_ALWAYS:
+		return true
+	case COLOR_NEVER:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+type diffLine struct {
+	op   diffmatchpatch.Operation
+	text string
+	// noNewline is true if the line is the last line without newline
+	noNewline bool
+}
+
+type diffHunk struct {
+	srcStart  int
+	srcCount  int
+	destStart int
+	destCount int
+	lines     []diffLine
+}
+
+// diffLines compare texts line by line
+func diffLines(srcText, destText string) []diffLine {
+	dmp := diffmatchpatch.New()
+	a, b, c := dmp.DiffLinesToChars(srcText, destText)
+	diffs := dmp.DiffMain(a, b, false)
+	diffs = dmp.DiffCharsToLines(diffs, c)
+
+	lines := []diffLine{}
+	for _, diff := range diffs {
+		texts := strings.Split(diff.Text, "\n")
+		for i, text := range texts {
+			if i == len(texts)-1 {
+				if text != "" {
+					lines = append(lines, diffLine{op: diff.Type, text: text, noNewline: true})
+				}
+				continue
+			}
+			lines = append(lines, diffLine{op: diff.Type, text: text})
+		}
+	}
+
+	return lines
+}
+
+// hasChange return true if any line is inserted or deleted
+func hasChange(lines []diffLine) bool {
+	for _, line := range lines {
+		if line.op != diffmatchpatch.DiffEqual {
+			return true
+		}
+	}
+
+	return false
+}
+
+// buildHunks group changed lines with context lines around them
+// changes separated by no more than twice of context lines are merged into one hunk
+func buildHunks(lines []diffLine, context int) []diffHunk {
+	// line numbers of src and dest before each line
+	srcLineNo := make([]int, len(lines)+1)
+	destLineNo := make([]int, len(lines)+1)
+	for i, line := range lines {
+		srcLineNo[i+1] = srcLineNo[i]
+		destLineNo[i+1] = destLineNo[i]
+		if line.op != diffmatchpatch.DiffInsert {
+			srcLineNo[i+1]++
+		}
+		if line.op != diffmatchpatch.DiffDelete {
+			destLineNo[i+1]++
+		}
+	}
+
+	hunks := []diffHunk{}
+	for i := 0; i < len(lines); {
+		if lines[i].op == diffmatchpatch.DiffEqual {
+			i++
+			continue
+		}
+
+		start := max(0, i-context)
+		end := i
+		for j := i; j < len(lines); {
+			if lines[j].op != diffmatchpatch.DiffEqual {
+				j++
+				end = j
+				continue
+			}
+			k := j
+			for k < len(lines) && lines[k].op == diffmatchpatch.DiffEqual {
+				k++
+			}
+			if k == len(lines) || k-j > 2*context {
+				break
+			}
+			j = k
+		}
+		stop := min(len(lines), end+context)
+
+		hunk := diffHunk{
+			srcCount:  srcLineNo[stop] - srcLineNo[start],
+			destCount: destLineNo[stop] - destLineNo[start],
+			lines:     lines[start:stop],
+		}
+		hunk.srcStart = srcLineNo[start]
+		if hunk.srcCount != 0 {
+			hunk.srcStart++
+		}
+		hunk.destStart = destLineNo[start]
+		if hunk.destCount != 0 {
+			hunk.destStart++
+		}
+		hunks = append(hunks, hunk)
+
+		i = stop
+	}
+
+	return hunks
+}
+
+// renderDiff print difference lines, limited to hunks if context is not negative
+func renderDiff(lines []diffLine, opt *DiffRenderOption) string {
+	var buf strings.Builder
+
+	if opt.context < 0 {
+		for _, line := range lines {
+			writeDiffLine(&buf, line, opt)
+		}
+		return buf.String()
+	}
+
+	for _, hunk := range buildHunks(lines, opt.context) {
+		header := fmt.Sprintf("@@ -%d,%d +%d,%d @@", hunk.srcStart, hunk.srcCount, hunk.destStart, hunk.destCount)
+		buf.WriteString(colorize(header, colorCyan, opt.color) + "\n")
+		for _, line := range hunk.lines {
+			writeDiffLine(&buf, line, opt)
+		}
+	}
+
+	return buf.String()
+}
+
+// unifiedDiff return unified diff which can be applied with patch command
+func unifiedDiff(srcName, destName, srcText, destText string, opt *DiffRenderOption) (bool, string) {
+	lines := diffLines(srcText, destText)
+	if !hasChange(lines) {
+		return false, ""
+	}
+
+	hunkOpt := *opt
+	hunkOpt.unified = true
+	if hunkOpt.context < 0 {
+		hunkOpt.context = 3
+	}
+
+	var buf strings.Builder
+	buf.WriteString(colorize("--- "+srcName, colorRed, opt.color) + "\n")
+	buf.WriteString(colorize("+++ "+destName, colorGreen, opt.color) + "\n")
+	buf.WriteString(renderDiff(lines, &hunkOpt))
+
+	return true, buf.String()
+}
+
+func writeDiffLine(buf *strings.Builder, line diffLine, opt *DiffRenderOption) {
+	prefix, color := "  ", ""
+	switch line.op {
+	case diffmatchpatch.DiffDelete:
+		prefix, color = "- ", colorRed
+	case diffmatchpatch.DiffInsert:
+		prefix, color = "+ ", colorGreen
+	}
+	if opt.unified {
+		prefix = prefix[:1]
+	}
+
+	buf.WriteString(colorize(prefix+line.text, color, opt.color) + "\n")
+	if opt.unified && line.noNewline {
+		buf.WriteString("\\ No newline at end of file\n")
+	}
+}
+
+// colorize wrap text with ANSI color escape sequence if enabled
+func colorize(text string, color string, enabled bool) string {
+	if !enabled || color == "" {
+		return text
+	}
+
+	return color + text + colorReset
+}
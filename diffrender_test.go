@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRenderDiff(t *testing.T) {
+	src := "a = 1\nb = 2\nc = 3\nd = 4\ne = 5\nf = 6\ng = 7\nh = 8\n"
+	dest := "a = 1\nb = 20\nc = 3\nd = 4\ne = 5\nf = 6\ng = 7\nh = 80\ni = 9\n"
+
+	cases := []struct {
+		name   string
+		opt    *DiffRenderOption
+		expect string
+	}{
+		{
+			name:   "all lines",
+			opt:    &DiffRenderOption{context: -1},
+			expect: "  a = 1\n- b = 2\n+ b = 20\n  c = 3\n  d = 4\n  e = 5\n  f = 6\n  g = 7\n- h = 8\n+ h = 80\n+ i = 9\n",
+		},
+		{
+			name:   "separated hunks",
+			opt:    &DiffRenderOption{context: 1},
+			expect: "@@ -1,3 +1,3 @@\n  a = 1\n- b = 2\n+ b = 20\n  c = 3\n@@ -7,2 +7,3 @@\n  g = 7\n- h = 8\n+ h = 80\n+ i = 9\n",
+		},
+		{
+			name:   "merged hunk",
+			opt:    &DiffRenderOption{context: 3},
+			expect: "@@ -1,8 +1,9 @@\n  a = 1\n- b = 2\n+ b = 20\n  c = 3\n  d = 4\n  e = 5\n  f = 6\n  g = 7\n- h = 8\n+ h = 80\n+ i = 9\n",
+		},
+		{
+			name:   "colorized without context",
+			opt:    &DiffRenderOption{context: 0, color: true},
+			expect: "\x1b[36m@@ -2,1 +2,1 @@\x1b[0m\n\x1b[31m- b = 2\x1b[0m\n\x1b[32m+ b = 20\x1b[0m\n\x1b[36m@@ -8,1 +8,2 @@\x1b[0m\n\x1b[31m- h = 8\x1b[0m\n\x1b[32m+ h = 80\x1b[0m\n\x1b[32m+ i = 9\x1b[0m\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := renderDiff(diffLines(src, dest), tt.opt)
+
+			if actual != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, actual)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	cases := []struct {
+		name          string
+		src           string
+		dest          string
+		expect        string
+		expectChanged bool
+	}{
+		{
+			name:          "no difference",
+			src:           "a = 1\n",
+			dest:          "a = 1\n",
+			expect:        "",
+			expectChanged: false,
+		},
+		{
+			name:          "insert line at the beginning",
+			src:           "b = 2\n",
+			dest:          "a = 1\nb = 2\n",
+			expect:        "--- terraform.tfvars\n+++ terraform.tfvars\n@@ -1,1 +1,2 @@\n+a = 1\n b = 2\n",
+			expectChanged: true,
+		},
+		{
+			name:          "create file",
+			src:           "",
+			dest:          "a = 1\n",
+			expect:        "--- terraform.tfvars\n+++ terraform.tfvars\n@@ -0,0 +1,1 @@\n+a = 1\n",
+			expectChanged: true,
+		},
+		{
+			name:          "last line without newline",
+			src:           "a = 1",
+			dest:          "a = 2\n",
+			expect:        "--- terraform.tfvars\n+++ terraform.tfvars\n@@ -1,1 +1,1 @@\n-a = 1\n\\ No newline at end of file\n+a = 2\n",
+			expectChanged: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, actual := unifiedDiff("terraform.tfvars", "terraform.tfvars", tt.src, tt.dest, &DiffRenderOption{context: -1})
+
+			if changed != tt.expectChanged {
+				t.Errorf("expect changed '%t', got '%t'", tt.expectChanged, changed)
+			}
+			if actual != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, actual)
+			}
+		})
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	var buf bytes.Buffer
+
+	if !useColor(COLOR_ALWAYS, &buf) {
+		t.Errorf("expect color with always mode")
+	}
+	if useColor(COLOR_NEVER, &buf) {
+		t.Errorf("expect no color with never mode")
+	}
+	if useColor(COLOR_AUTO, &buf) {
+		t.Errorf("expect no color for non terminal writer")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if !useColor(COLOR_ALWAYS, &buf) {
+		t.Errorf("expect always mode to override NO_COLOR")
+	}
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	DIFF_ADDED     = "added"
+	DIFF_REMOVED   = "removed"
+	DIFF_CHANGED   = "changed"
+	DIFF_UNCHANGED = "unchanged"
+	// DIFF_TYPE_CHANGED is reported only if type changes are enabled, such as 3000 and "3000"
+	DIFF_TYPE_CHANGED = "type-changed"
+)
+
+// DiffEntry is structured difference of a variable between Terraform Cloud (old) and local file (new)
+type DiffEntry struct {
+	Key       string           `json:"key"`
+	Change    string           `json:"change"`
+	Old       *string          `json:"old,omitempty"`
+	New       *string          `json:"new,omitempty"`
+	Sensitive bool             `json:"sensitive"`
+	Metadata  []MetadataChange `json:"metadata,omitempty"`
+}
+
+// MetadataChange is difference of a variable attribute other than value
+type MetadataChange struct {
+	Attribute string `json:"attribute"`
+	Old       string `json:"old"`
+	New       string `json:"new"`
+}
+
+// diffComparator compare values and metadata of variables
+type diffComparator struct {
+	oldValues   map[string]cty.Value
+	newValues   map[string]cty.Value
+	metadata    func(*tfe.Variable, *tfe.Variable) []MetadataChange
+	typeChanges bool
+}
+
+// valueChange return change type of value by comparing parsed values
+// type only change is treated as unchanged unless typeChanges is enabled
+func (c *diffComparator) valueChange(vOld *tfe.Variable, vNew *tfe.Variable) string {
+	oldValue, okOld := c.oldValues[vOld.Key]
+	newValue, okNew := c.newValues[vNew.Key]
+	if !okOld || !okNew {
+		if vOld.Value == vNew.Value {
+			return DIFF_UNCHANGED
+		}
+		return DIFF_CHANGED
+	}
+
+	change := CompareValues(oldValue, newValue)
+	if change == DIFF_TYPE_CHANGED && !c.typeChanges {
+		return DIFF_UNCHANGED
+	}
+
+	return change
+}
+
+// metadataChanges return attribute changes other than value
+func (c *diffComparator) metadataChanges(vOld *tfe.Variable, vNew *tfe.Variable) []MetadataChange {
+	if c.metadata == nil {
+		return nil
+	}
+
+	return c.metadata(vOld, vNew)
+}
+
+// buildDiffEntries compare old and new variables by key, sorted by key
+// values of sensitive variables are masked
+func buildDiffEntries(oldVars []*tfe.Variable, newVars []*tfe.Variable, comparator *diffComparator) []*DiffEntry {
+	previous := map[string]*tfe.Variable{}
+	for _, v := range oldVars {
+		previous[v.Key] = v
+	}
+	current := map[string]*tfe.Variable{}
+	for _, v := range newVars {
+		current[v.Key] = v
+	}
+	sensitive := sensitiveKeys(oldVars, newVars)
+
+	entries := []*DiffEntry{}
+	for _, vNew := range newVars {
+		entry := &DiffEntry{
+			Key:       vNew.Key,
+			Sensitive: sensitive[vNew.Key],
+		}
+		newValue := redactValue(vNew.Value, entry.Sensitive)
+		entry.New = &newValue
+
+		vOld, ok := previous[vNew.Key]
+		if !ok {
+			entry.Change = DIFF_ADDED
+			entries = append(entries, entry)
+			continue
+		}
+
+		oldValue := redactValue(vOld.Value, entry.Sensitive)
+		entry.Old = &oldValue
+		entry.Metadata = comparator.metadataChanges(vOld, vNew)
+
+		// value of sensitive variable cannot be retrieved from Terraform Cloud
+		entry.Change = DIFF_UNCHANGED
+		if !entry.Sensitive {
+			entry.Change = comparator.valueChange(vOld, vNew)
+		}
+		if entry.Change == DIFF_UNCHANGED && len(entry.Metadata) != 0 {
+			entry.Change = DIFF_CHANGED
+		}
+		entries = append(entries, entry)
+	}
+	for _, vOld := range oldVars {
+		if _, ok := current[vOld.Key]; ok {
+			continue
+		}
+		oldValue := redactValue(vOld.Value, sensitive[vOld.Key])
+		entries = append(entries, &DiffEntry{
+			Key:       vOld.Key,
+			Change:    DIFF_REMOVED,
+			Old:       &oldValue,
+			Sensitive: sensitive[vOld.Key],
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+
+	return entries
+}
+
+// metadataChanges return attributes changed other than value when remote variable is updated with local one
+// changes are computed from the operation push applies, so that diff reports exactly what push changes
+func metadataChanges(remote *tfe.Variable, local *tfe.Variable, compareDescription bool) []MetadataChange {
+	changes := newPushUpdate(local, remote, compareDescription).metadataChanges()
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return changes
+}
+
+// variableMetadataChanges return changes of sensitive, hcl, category and description attributes
+func variableMetadataChanges(before *tfe.Variable, after *tfe.Variable) []MetadataChange {
+	changes := []MetadataChange{}
+
+	if before.Sensitive != after.Sensitive {
+		changes = append(changes, MetadataChange{
+			Attribute: "sensitive",
+			Old:       strconv.FormatBool(before.Sensitive),
+			New:       strconv.FormatBool(after.Sensitive),
+		})
+	}
+	if before.HCL != after.HCL {
+		changes = append(changes, MetadataChange{
+			Attribute: "hcl",
+			Old:       strconv.FormatBool(before.HCL),
+			New:       strconv.FormatBool(after.HCL),
+		})
+	}
+	if variableCategory(before) != variableCategory(after) {
+		changes = append(changes, MetadataChange{
+			Attribute: "category",
+			Old:       string(variableCategory(before)),
+			New:       string(variableCategory(after)),
+		})
+	}
+	if before.Description != after.Description {
+		changes = append(changes, MetadataChange{
+			Attribute: "description",
+			Old:       before.Description,
+			New:       after.Description,
+		})
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+// formatMetadataChange return terraform plan style line of attribute change
+func formatMetadataChange(key string, change MetadataChange) string {
+	if change.Attribute == "description" {
+		return fmt.Sprintf("~ %s: %s %q -> %q", key, change.Attribute, change.Old, change.New)
+	}
+
+	return fmt.Sprintf("~ %s: %s %s -> %s", key, change.Attribute, change.Old, change.New)
+}
+
+// writeMetadataChanges print metadata changes of entries
+// return true if any change is printed
+func writeMetadataChanges(w io.Writer, entries []*DiffEntry, color bool) bool {
+	written := false
+
+	for _, entry := range entries {
+		for _, change := range entry.Metadata {
+			fmt.Fprintln(w, colorize(formatMetadataChange(entry.Key, change), colorYellow, color))
+			written = true
+		}
+	}
+
+	return written
+}
+
+// unchangedKeys return keys defined in both whose values are equivalent or cannot be compared by sensitivity
+func unchangedKeys(oldVars []*tfe.Variable, newVars []*tfe.Variable, comparator *diffComparator) map[string]bool {
+	keys := map[string]bool{}
+	sensitive := sensitiveKeys(oldVars, newVars)
+
+	for _, vOld := range oldVars {
+		for _, vNew := range newVars {
+			if vOld.Key != vNew.Key {
+				continue
+			}
+			if sensitive[vOld.Key] || comparator.valueChange(vOld, vNew) == DIFF_UNCHANGED {
+				keys[vOld.Key] = true
+			}
+		}
+	}
+
+	return keys
+}
+
+// hasDiffEntryChange return true if any entry is not unchanged
+func hasDiffEntryChange(entries []*DiffEntry) bool {
+	for _, entry := range entries {
+		if entry.Change != DIFF_UNCHANGED {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeDiffJSON print entries as JSON array
+func writeDiffJSON(w io.Writer, entries []*DiffEntry) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(entries)
+}
+
+// writeDiffSummary print keys which exist only in destination, only in source or in both
+// sections are titled with names of sides, such as var-file and workspace
+func writeDiffSummary(w io.Writer, entries []*DiffEntry, fromName string, toName string) {
+	sections := []struct {
+		title string
+		keys  []string
+	}{
+		{title: "Only in " + toName},
+		{title: "Only in " + fromName},
+		{title: "In both"},
+	}
+
+	for _, entry := range entries {
+		switch entry.Change {
+		case DIFF_ADDED:
+			sections[0].keys = append(sections[0].keys, entry.Key)
+		case DIFF_REMOVED:
+			sections[1].keys = append(sections[1].keys, entry.Key)
+		default:
+			sections[2].keys = append(sections[2].keys, entry.Key)
+		}
+	}
+
+	for _, section := range sections {
+		if len(section.keys) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s (%d):\n", section.title, len(section.keys))
+		for _, key := range section.keys {
+			fmt.Fprintf(w, "  %s\n", key)
+		}
+	}
+}
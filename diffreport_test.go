@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestBuildDiffEntries(t *testing.T) {
+	cases := []struct {
+		name               string
+		remoteVars         []*tfe.Variable
+		localVars          []*tfe.Variable
+		compareDescription bool
+		expect             []*DiffEntry
+	}{
+		{
+			name:       "no variables",
+			remoteVars: []*tfe.Variable{},
+			localVars:  []*tfe.Variable{},
+			expect:     []*DiffEntry{},
+		},
+		{
+			name: "classify changes sorted by key",
+			remoteVars: []*tfe.Variable{
+				{Key: "unchanged", Value: "value"},
+				{Key: "changed", Value: "old"},
+				{Key: "removed", Value: "value"},
+			},
+			localVars: []*tfe.Variable{
+				{Key: "unchanged", Value: "value"},
+				{Key: "changed", Value: "new"},
+				{Key: "added", Value: "value"},
+			},
+			expect: []*DiffEntry{
+				{Key: "added", Change: DIFF_ADDED, New: tfe.String("value")},
+				{Key: "changed", Change: DIFF_CHANGED, Old: tfe.String("old"), New: tfe.String("new")},
+				{Key: "removed", Change: DIFF_REMOVED, Old: tfe.String("value")},
+				{Key: "unchanged", Change: DIFF_UNCHANGED, Old: tfe.String("value"), New: tfe.String("value")},
+			},
+		},
+		{
+			name: "mask sensitive values",
+			remoteVars: []*tfe.Variable{
+				{Key: "db_password", Sensitive: true},
+				{Key: "api_token", Sensitive: true},
+			},
+			localVars: []*tfe.Variable{
+				{Key: "db_password", Value: "supersecret"},
+			},
+			expect: []*DiffEntry{
+				{Key: "api_token", Change: DIFF_REMOVED, Old: tfe.String(sensitiveMask), Sensitive: true},
+				{Key: "db_password", Change: DIFF_UNCHANGED, Old: tfe.String(sensitiveMask), New: tfe.String(sensitiveMask), Sensitive: true},
+			},
+		},
+		{
+			name: "detect metadata changes",
+			remoteVars: []*tfe.Variable{
+				{Key: "region", Value: "ap-northeast-1", Description: "old description"},
+				{Key: "zones", Value: `["ap-northeast-1a"]`, HCL: false},
+				{Key: "no_description", Value: "value", Description: "remote description"},
+			},
+			localVars: []*tfe.Variable{
+				{Key: "region", Value: "ap-northeast-1", Description: "new description"},
+				{Key: "zones", Value: `["ap-northeast-1a"]`, HCL: true},
+				{Key: "no_description", Value: "value"},
+			},
+			compareDescription: true,
+			expect: []*DiffEntry{
+				{Key: "no_description", Change: DIFF_UNCHANGED, Old: tfe.String("value"), New: tfe.String("value")},
+				{
+					Key:      "region",
+					Change:   DIFF_CHANGED,
+					Old:      tfe.String("ap-northeast-1"),
+					New:      tfe.String("ap-northeast-1"),
+					Metadata: []MetadataChange{{Attribute: "description", Old: "old description", New: "new description"}},
+				},
+				{Key: "zones", Change: DIFF_UNCHANGED, Old: tfe.String(`["ap-northeast-1a"]`), New: tfe.String(`["ap-northeast-1a"]`)},
+			},
+		},
+		{
+			name: "ignore descriptions and primitive hcl flag",
+			remoteVars: []*tfe.Variable{
+				{Key: "port", Value: "3000", HCL: true, Description: "remote description"},
+				{Key: "db_password", HCL: true, Sensitive: true},
+			},
+			localVars: []*tfe.Variable{
+				{Key: "port", Value: "3000", Description: "comment"},
+				{Key: "db_password", Value: sensitiveMask},
+			},
+			expect: []*DiffEntry{
+				{Key: "db_password", Change: DIFF_UNCHANGED, Old: tfe.String(sensitiveMask), New: tfe.String(sensitiveMask), Sensitive: true},
+				{Key: "port", Change: DIFF_UNCHANGED, Old: tfe.String("3000"), New: tfe.String("3000")},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			compare := func(old *tfe.Variable, new *tfe.Variable) []MetadataChange {
+				return metadataChanges(old, new, tt.compareDescription)
+			}
+
+			actual := buildDiffEntries(tt.remoteVars, tt.localVars, &diffComparator{metadata: compare})
+
+			if !reflect.DeepEqual(tt.expect, actual) {
+				t.Errorf("expect '%+v', got '%+v'", tt.expect, actual)
+			}
+		})
+	}
+}
+
+func TestWriteDiffSummary(t *testing.T) {
+	entries := []*DiffEntry{
+		{Key: "added", Change: DIFF_ADDED},
+		{Key: "changed", Change: DIFF_CHANGED},
+		{Key: "removed", Change: DIFF_REMOVED},
+		{Key: "unchanged", Change: DIFF_UNCHANGED},
+	}
+	cases := []struct {
+		name     string
+		fromName string
+		toName   string
+		expect   string
+	}{
+		{
+			name:     "workspace and var-file",
+			fromName: "ws-test",
+			toName:   "terraform.tfvars",
+			expect:   "Only in terraform.tfvars (1):\n  added\nOnly in ws-test (1):\n  removed\nIn both (2):\n  changed\n  unchanged\n",
+		},
+		{
+			name:     "two workspaces",
+			fromName: "org/staging",
+			toName:   "org/production",
+			expect:   "Only in org/production (1):\n  added\nOnly in org/staging (1):\n  removed\nIn both (2):\n  changed\n  unchanged\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeDiffSummary(&buf, entries, tt.fromName, tt.toName)
+
+			if buf.String() != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, buf.String())
+			}
+		})
+	}
+}
+
+func TestVariableMetadataChanges(t *testing.T) {
+	before := &tfe.Variable{Key: "db_password", Category: tfe.CategoryTerraform, Description: "old"}
+	after := &tfe.Variable{Key: "db_password", Sensitive: true, HCL: true, Category: tfe.CategoryEnv, Description: "new"}
+	expect := []string{
+		"~ db_password: sensitive false -> true",
+		"~ db_password: hcl false -> true",
+		"~ db_password: category terraform -> env",
+		`~ db_password: description "old" -> "new"`,
+	}
+
+	changes := variableMetadataChanges(before, after)
+	actual := []string{}
+	for _, change := range changes {
+		actual = append(actual, formatMetadataChange(before.Key, change))
+	}
+
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect '%v', got '%v'", expect, actual)
+	}
+	if changes := variableMetadataChanges(before, before); changes != nil {
+		t.Errorf("expect no changes, got '%v'", changes)
+	}
+}
+
+func TestDiffComparatorValueChange(t *testing.T) {
+	cases := []struct {
+		name        string
+		oldValue    cty.Value
+		newValue    cty.Value
+		typeChanges bool
+		expect      string
+	}{
+		{
+			name:     "same string",
+			oldValue: cty.StringVal("3000"),
+			newValue: cty.StringVal("3000"),
+			expect:   DIFF_UNCHANGED,
+		},
+		{
+			name:     "number and string",
+			oldValue: cty.StringVal("3000"),
+			newValue: cty.NumberIntVal(3000),
+			expect:   DIFF_UNCHANGED,
+		},
+		{
+			name:        "number and string with type changes",
+			oldValue:    cty.StringVal("3000"),
+			newValue:    cty.NumberIntVal(3000),
+			typeChanges: true,
+			expect:      DIFF_TYPE_CHANGED,
+		},
+		{
+			name:     "map in different order",
+			oldValue: cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("1"), "b": cty.StringVal("2")}),
+			newValue: cty.MapVal(map[string]cty.Value{"b": cty.StringVal("2"), "a": cty.StringVal("1")}),
+			expect:   DIFF_UNCHANGED,
+		},
+		{
+			name:     "different value",
+			oldValue: cty.TupleVal([]cty.Value{cty.StringVal("a")}),
+			newValue: cty.TupleVal([]cty.Value{cty.StringVal("b")}),
+			expect:   DIFF_CHANGED,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			comparator := &diffComparator{
+				oldValues:   map[string]cty.Value{"key": tt.oldValue},
+				newValues:   map[string]cty.Value{"key": tt.newValue},
+				typeChanges: tt.typeChanges,
+			}
+
+			actual := comparator.valueChange(&tfe.Variable{Key: "key"}, &tfe.Variable{Key: "key"})
+
+			if actual != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, actual)
+			}
+		})
+	}
+}
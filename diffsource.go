@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// DiffSource is one side of diff, either a workspace in Terraform Cloud or a local var-file
+type DiffSource struct {
+	organization string
+	workspace    string
+	varFile      string
+}
+
+// NewDiffSource parse ORG/WORKSPACE or path of local var-file
+// spec is treated as var-file if the file exists or has .tfvars extension
+func NewDiffSource(spec string) (*DiffSource, error) {
+	if _, err := os.Stat(spec); err == nil || strings.HasSuffix(spec, ".tfvars") {
+		return &DiffSource{varFile: spec}, nil
+	}
+
+	org, ws, err := parseWorkspaceSpec(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid diff source '%s': specify ORG/WORKSPACE or existing var-file", spec)
+	}
+
+	return &DiffSource{organization: org, workspace: ws}, nil
+}
+
+// parseWorkspaceSpec split ORG/WORKSPACE into organization and workspace name
+func parseWorkspaceSpec(spec string) (string, string, error) {
+	org, ws, found := strings.Cut(spec, "/")
+	if !found || org == "" || ws == "" || strings.Contains(ws, "/") {
+		return "", "", fmt.Errorf("invalid workspace '%s': specify ORG/WORKSPACE", spec)
+	}
+
+	return org, ws, nil
+}
+
+func (s *DiffSource) String() string {
+	if s.isLocal() {
+		return s.varFile
+	}
+
+	return s.organization + "/" + s.workspace
+}
+
+func (s *DiffSource) isLocal() bool {
+	return s.varFile != ""
+}
+
+// diffSide is variables loaded from one side of diff
+type diffSide struct {
+	name   string
+	vars   []*tfe.Variable
+	tfvars *Tfvars // contents of local var-file, nil if loaded from Terraform Cloud
+}
+
+// newRemoteDiffSide list variables of workspace selected by diff options
+func newRemoteDiffSide(ctx context.Context, workspaceId string, tfeVariables tfe.Variables, tfeVariableSets tfe.VariableSets, tfeVariableSetVariables tfe.VariableSetVariables, diffOpt *DiffOption) (*diffSide, error) {
+	varList, err := tfeVariables.List(ctx, workspaceId, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list variables")
+		return nil, err
+	}
+	vars := append([]*tfe.Variable{}, varList.Items...)
+	if diffOpt.includeVariableSet {
+		variableSetVariables, err := listVariableSetVariables(ctx, workspaceId, tfeVariableSets, tfeVariableSetVariables)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to list VariableSetVariables")
+			return nil, err
+		}
+		vars = append(vars, variableSetVariables...)
+	}
+	if !diffOpt.includeEnv && !diffOpt.filter.requireEnv() {
+		vars = FilterEnv(vars)
+	}
+
+	return &diffSide{name: workspaceId, vars: diffOpt.filter.Filter(vars)}, nil
+}
+
+// newLocalDiffSide read variables of var-file selected by diff options
+func newLocalDiffSide(varFile string, diffOpt *DiffOption) (*diffSide, error) {
+	vf, err := NewTfvarsFile(varFile)
+	if err != nil {
+		return nil, err
+	}
+	vf = vf.selectVariables(diffOpt.filter)
+
+	return &diffSide{name: varFile, vars: vf.vars, tfvars: vf}, nil
+}
+
+// loadDiffSide read variables from workspace or var-file specified by source
+func loadDiffSide(ctx context.Context, source *DiffSource, tfeWorkspaces tfe.Workspaces, tfeVariables tfe.Variables, tfeVariableSets tfe.VariableSets, tfeVariableSetVariables tfe.VariableSetVariables, diffOpt *DiffOption) (*diffSide, error) {
+	if source.isLocal() {
+		return newLocalDiffSide(source.varFile, diffOpt)
+	}
+
+	w, err := tfeWorkspaces.Read(ctx, source.organization, source.workspace)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to access workspace %s", source)
+		return nil, err
+	}
+
+	side, err := newRemoteDiffSide(ctx, w.ID, tfeVariables, tfeVariableSets, tfeVariableSetVariables, diffOpt)
+	if err != nil {
+		return nil, err
+	}
+	side.name = source.String()
+
+	return side, nil
+}
+
+// maskedVars return variables whose values are masked if the key is sensitive
+func (s *diffSide) maskedVars(sensitive map[string]bool) []*tfe.Variable {
+	if s.tfvars != nil {
+		return s.tfvars.redact(sensitive).vars
+	}
+
+	return redactVariables(s.vars, sensitive)
+}
+
+// values return parsed values with sensitive values masked
+func (s *diffSide) values(sensitive map[string]bool) map[string]cty.Value {
+	vf := s.render(sensitive)
+	if vf == nil {
+		return map[string]cty.Value{}
+	}
+
+	return vf.values()
+}
+
+// render return tfvars file contents with sensitive values masked
+func (s *diffSide) render(sensitive map[string]bool) *Tfvars {
+	if s.tfvars != nil {
+		return s.tfvars.redact(sensitive)
+	}
+
+	// write masked value as attribute instead of comment to compare with the other side
+	vars := []*tfe.Variable{}
+	for _, v := range redactVariables(s.vars, sensitive) {
+		masked := *v
+		masked.Sensitive = false
+		vars = append(vars, &masked)
+	}
+
+	return NewTfvarsVariable(vars)
+}
+
+// metadataComparator return function to compare metadata of variables loaded from sides
+// local var-file has no metadata other than hcl and description
+func metadataComparator(from *diffSide, to *diffSide, compareDescription bool) func(*tfe.Variable, *tfe.Variable) []MetadataChange {
+	switch {
+	case from.tfvars == nil && to.tfvars == nil:
+		return variableMetadataChanges
+	case from.tfvars == nil:
+		return func(old *tfe.Variable, new *tfe.Variable) []MetadataChange {
+			return metadataChanges(old, new, compareDescription)
+		}
+	case to.tfvars == nil:
+		return func(old *tfe.Variable, new *tfe.Variable) []MetadataChange {
+			changes := metadataChanges(new, old, compareDescription)
+			for i := range changes {
+				changes[i].Old, changes[i].New = changes[i].New, changes[i].Old
+			}
+			return changes
+		}
+	}
+
+	return func(*tfe.Variable, *tfe.Variable) []MetadataChange {
+		return nil
+	}
+}
+
+// redactSecretSides return copies of sides whose secret-looking values are masked
+// values are compared before masking so that changed values are still reported
+func redactSecretSides(from *diffSide, to *diffSide, sensitive map[string]bool, typeChanges bool) (*diffSide, *diffSide) {
+	comparator := &diffComparator{
+		oldValues:   from.values(sensitive),
+		newValues:   to.values(sensitive),
+		typeChanges: typeChanges,
+	}
+	fromMasks := map[string]string{}
+	toMasks := map[string]string{}
+	for _, vFrom := range from.vars {
+		if !sensitive[vFrom.Key] && looksSecret(vFrom) {
+			fromMasks[vFrom.Key] = redactedMask
+		}
+	}
+	for _, vTo := range to.vars {
+		if sensitive[vTo.Key] {
+			continue
+		}
+		_, secret := fromMasks[vTo.Key]
+		if !secret && !looksSecret(vTo) {
+			continue
+		}
+		toMasks[vTo.Key] = redactedMask
+
+		for _, vFrom := range from.vars {
+			if vFrom.Key != vTo.Key {
+				continue
+			}
+			fromMasks[vFrom.Key] = redactedMask
+			if comparator.valueChange(vFrom, vTo) != DIFF_UNCHANGED {
+				toMasks[vTo.Key] = redactedChangedMask
+			}
+		}
+	}
+
+	return from.mask(fromMasks), to.mask(toMasks)
+}
+
+// mask return copy of side whose values are replaced with masks by key
+func (s *diffSide) mask(masks map[string]string) *diffSide {
+	masked := &diffSide{
+		name: s.name,
+		vars: maskVariables(s.vars, masks),
+	}
+	if s.tfvars != nil {
+		masked.tfvars = s.tfvars.mask(masks)
+		masked.vars = masked.tfvars.vars
+	}
+
+	return masked
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	tfe "github.com/hashicorp/go-tfe"
+)
+
+// BuildDotenvFile generate dotenv file contents from env category variables
+// sensitive variables are written according to sensitiveOpt, localEnvFile is used for keep-local strategy
+func BuildDotenvFile(vars []*tfe.Variable, sensitiveOpt *SensitiveOption, localEnvFile []byte) []byte {
+	var buf bytes.Buffer
+
+	localValues := parseDotenv(localEnvFile)
+	for _, v := range vars {
+		if v.Category != tfe.CategoryEnv {
+			continue
+		}
+		if line := dotenvLine(v, sensitiveOpt.getStrategy(), localValues); line != "" {
+			buf.WriteString(line + "\n")
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// MergeDotenvFile merge env category variables into localEnvFile
+// lines of pulled variables are replaced in place, and other lines and comments are kept as is
+func MergeDotenvFile(vars []*tfe.Variable, sensitiveOpt *SensitiveOption, localEnvFile []byte) []byte {
+	var buf bytes.Buffer
+
+	localValues := parseDotenv(localEnvFile)
+	lines := map[string]string{}
+	keys := []string{}
+	for _, v := range vars {
+		if v.Category != tfe.CategoryEnv {
+			continue
+		}
+		lines[v.Key] = dotenvLine(v, sensitiveOpt.getStrategy(), localValues)
+		keys = append(keys, v.Key)
+	}
+
+	written := map[string]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(localEnvFile))
+	for scanner.Scan() {
+		line := scanner.Text()
+		key := dotenvLineKey(line)
+		replaced, ok := lines[key]
+		if !ok {
+			buf.WriteString(line + "\n")
+			continue
+		}
+		if !written[key] && replaced != "" {
+			buf.WriteString(replaced + "\n")
+		}
+		written[key] = true
+	}
+	for _, key := range keys {
+		if !written[key] && lines[key] != "" {
+			buf.WriteString(lines[key] + "\n")
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// dotenvLine return line of variable in dotenv file, or empty string if omitted
+func dotenvLine(v *tfe.Variable, strategy string, localValues map[string]string) string {
+	if !v.Sensitive {
+		return fmt.Sprintf("%s=%s", v.Key, quoteDotenv(v.Value))
+	}
+
+	switch strategy {
+	case SENSITIVE_OMIT:
+		return ""
+	case SENSITIVE_KEEP_LOCAL:
+		if value, ok := localValues[v.Key]; ok {
+			return fmt.Sprintf("%s=%s", v.Key, quoteDotenv(value))
+		}
+	case SENSITIVE_PLACEHOLDER:
+		return fmt.Sprintf("%s=\"\"", v.Key)
+	}
+
+	return fmt.Sprintf("# %s=***", v.Key)
+}
+
+// dotenvLineKey return key assigned in line, including comment written for sensitive variable
+// empty string is returned for other comments and blank lines
+func dotenvLineKey(line string) string {
+	line = strings.TrimSpace(line)
+	if comment, found := strings.CutPrefix(line, "#"); found {
+		key, masked := strings.CutSuffix(strings.TrimSpace(comment), "=***")
+		if !masked {
+			return ""
+		}
+		return strings.TrimSpace(key)
+	}
+
+	key, _, found := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+	if !found {
+		return ""
+	}
+
+	return strings.TrimSpace(key)
+}
+
+// redactDotenv return copy of dotenv file contents whose sensitive values are masked
+func redactDotenv(src []byte, sensitive map[string]bool) []byte {
+	var buf bytes.Buffer
+
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for scanner.Scan() {
+		line := scanner.Text()
+		assignment := strings.TrimPrefix(strings.TrimSpace(line), "export ")
+		key, _, found := strings.Cut(assignment, "=")
+		if found && !strings.HasPrefix(assignment, "#") && sensitive[strings.TrimSpace(key)] {
+			line = strings.TrimSpace(key) + "=" + quoteDotenv(sensitiveMask)
+		}
+		buf.WriteString(line + "\n")
+	}
+
+	return buf.Bytes()
+}
+
+// quoteDotenv return double quoted value escaped for dotenv file
+func quoteDotenv(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		"`", "\\`",
+		"\n", `\n`,
+		"\r", `\r`,
+	)
+
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// parseDotenv read KEY=VALUE lines of dotenv file
+func parseDotenv(src []byte) map[string]string {
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			value = unquoteDotenv(value[1 : len(value)-1])
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+
+	return values
+}
+
+func unquoteDotenv(value string) string {
+	var buf strings.Builder
+
+	escaped := false
+	for _, r := range value {
+		if !escaped {
+			if r == '\\' {
+				escaped = true
+			} else {
+				buf.WriteRune(r)
+			}
+			continue
+		}
+
+		switch r {
+		case 'n':
+			buf.WriteRune('\n')
+		case 'r':
+			buf.WriteRune('\r')
+		default:
+			buf.WriteRune(r)
+		}
+		escaped = false
+	}
+	if escaped {
+		buf.WriteRune('\\')
+	}
+
+	return buf.String()
+}
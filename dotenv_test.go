@@ -0,0 +1,145 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	tfe "github.com/hashicorp/go-tfe"
+)
+
+func TestBuildDotenvFile(t *testing.T) {
+	vars := []*tfe.Variable{
+		{Key: "region", Value: "ap-northeast-1", Category: tfe.CategoryTerraform},
+		{Key: "AWS_REGION", Value: "ap-northeast-1", Category: tfe.CategoryEnv},
+		{Key: "GREETING", Value: "say \"hello\" to $USER\nbye", Category: tfe.CategoryEnv},
+		{Key: "AWS_SECRET_ACCESS_KEY", Category: tfe.CategoryEnv, Sensitive: true},
+	}
+
+	cases := []struct {
+		name     string
+		strategy string
+		local    []byte
+		expect   string
+	}{
+		{
+			name:     "comment sensitive variable",
+			strategy: SENSITIVE_COMMENT,
+			expect:   "AWS_REGION=\"ap-northeast-1\"\nGREETING=\"say \\\"hello\\\" to \\$USER\\nbye\"\n# AWS_SECRET_ACCESS_KEY=***\n",
+		},
+		{
+			name:     "omit sensitive variable",
+			strategy: SENSITIVE_OMIT,
+			expect:   "AWS_REGION=\"ap-northeast-1\"\nGREETING=\"say \\\"hello\\\" to \\$USER\\nbye\"\n",
+		},
+		{
+			name:     "keep local value of sensitive variable",
+			strategy: SENSITIVE_KEEP_LOCAL,
+			local:    []byte("# credentials\nexport AWS_SECRET_ACCESS_KEY='secret'\n"),
+			expect:   "AWS_REGION=\"ap-northeast-1\"\nGREETING=\"say \\\"hello\\\" to \\$USER\\nbye\"\nAWS_SECRET_ACCESS_KEY=\"secret\"\n",
+		},
+		{
+			name:     "placeholder for sensitive variable",
+			strategy: SENSITIVE_PLACEHOLDER,
+			expect:   "AWS_REGION=\"ap-northeast-1\"\nGREETING=\"say \\\"hello\\\" to \\$USER\\nbye\"\nAWS_SECRET_ACCESS_KEY=\"\"\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := BuildDotenvFile(vars, &SensitiveOption{strategy: tt.strategy}, tt.local)
+
+			if string(actual) != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, actual)
+			}
+		})
+	}
+}
+
+func TestMergeDotenvFile(t *testing.T) {
+	vars := []*tfe.Variable{
+		{Key: "region", Value: "ap-northeast-1", Category: tfe.CategoryTerraform},
+		{Key: "AWS_REGION", Value: "ap-northeast-1", Category: tfe.CategoryEnv},
+		{Key: "AWS_SECRET_ACCESS_KEY", Category: tfe.CategoryEnv, Sensitive: true},
+		{Key: "TF_LOG", Value: "INFO", Category: tfe.CategoryEnv},
+	}
+	local := []byte("# aws\nexport AWS_REGION=us-east-1\nAWS_PROFILE=dev\n# AWS_SECRET_ACCESS_KEY=***\n")
+
+	cases := []struct {
+		name     string
+		strategy string
+		local    []byte
+		expect   string
+	}{
+		{
+			name:     "replace pulled variables and keep others",
+			strategy: SENSITIVE_COMMENT,
+			local:    local,
+			expect:   "# aws\nAWS_REGION=\"ap-northeast-1\"\nAWS_PROFILE=dev\n# AWS_SECRET_ACCESS_KEY=***\nTF_LOG=\"INFO\"\n",
+		},
+		{
+			name:     "remove omitted sensitive variable",
+			strategy: SENSITIVE_OMIT,
+			local:    []byte("AWS_SECRET_ACCESS_KEY=secret\nAWS_PROFILE=dev\n"),
+			expect:   "AWS_PROFILE=dev\nAWS_REGION=\"ap-northeast-1\"\nTF_LOG=\"INFO\"\n",
+		},
+		{
+			name:     "keep local value of sensitive variable",
+			strategy: SENSITIVE_KEEP_LOCAL,
+			local:    []byte("AWS_SECRET_ACCESS_KEY='secret'\nAWS_PROFILE=dev\n"),
+			expect:   "AWS_SECRET_ACCESS_KEY=\"secret\"\nAWS_PROFILE=dev\nAWS_REGION=\"ap-northeast-1\"\nTF_LOG=\"INFO\"\n",
+		},
+		{
+			name:     "empty local file",
+			strategy: SENSITIVE_COMMENT,
+			expect:   "AWS_REGION=\"ap-northeast-1\"\n# AWS_SECRET_ACCESS_KEY=***\nTF_LOG=\"INFO\"\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := MergeDotenvFile(vars, &SensitiveOption{strategy: tt.strategy}, tt.local)
+
+			if string(actual) != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, actual)
+			}
+		})
+	}
+}
+
+func TestParseDotenv(t *testing.T) {
+	src := []byte(`# comment
+PLAIN=value
+export EXPORTED=exported
+DOUBLE="say \"hello\"\nbye \$HOME"
+SINGLE='raw \n value'
+INVALID
+`)
+	expect := map[string]string{
+		"PLAIN":    "value",
+		"EXPORTED": "exported",
+		"DOUBLE":   "say \"hello\"\nbye $HOME",
+		"SINGLE":   `raw \n value`,
+	}
+
+	actual := parseDotenv(src)
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect '%v', got '%v'", expect, actual)
+	}
+
+	for key, value := range expect {
+		quoted := parseDotenv([]byte(key + "=" + quoteDotenv(value)))
+		if quoted[key] != value {
+			t.Errorf("expect '%s' after quote and parse, got '%s'", value, quoted[key])
+		}
+	}
+}
+
+func TestRedactDotenv(t *testing.T) {
+	src := []byte("AWS_REGION=\"ap-northeast-1\"\nexport AWS_SECRET_ACCESS_KEY=\"secret\"\n# AWS_SECRET_ACCESS_KEY=***\n")
+	expect := "AWS_REGION=\"ap-northeast-1\"\nAWS_SECRET_ACCESS_KEY=\"(sensitive)\"\n# AWS_SECRET_ACCESS_KEY=***\n"
+
+	actual := redactDotenv(src, map[string]bool{"AWS_SECRET_ACCESS_KEY": true})
+	if string(actual) != expect {
+		t.Errorf("expect '%s', got '%s'", expect, actual)
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/rs/zerolog/log"
+)
+
+// ageIdentityFile is identity file to decrypt encrypted tfvars files, set by global option
+var ageIdentityFile string
+
+// ageHeader is the first line of age encrypted file in binary format
+const ageHeader = "age-encryption.org/v1\n"
+
+// sopsValue is value encrypted by SOPS, ENC[AES256_GCM,data:...,iv:...,tag:...,type:...]
+var sopsValue = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.*),iv:(.+),tag:(.+),type:(.+)\]$`)
+
+// SopsFile is file encrypted by SOPS as binary, whose whole contents are kept in data
+type SopsFile struct {
+	Data string        `json:"data"`
+	Sops *SopsMetadata `json:"sops"`
+}
+
+// SopsMetadata is metadata of SOPS required to decrypt data with age
+type SopsMetadata struct {
+	Age          []*SopsAgeKey `json:"age"`
+	LastModified string        `json:"lastmodified"`
+	MAC          string        `json:"mac"`
+}
+
+// SopsAgeKey is data key of SOPS encrypted for an age recipient
+type SopsAgeKey struct {
+	Recipient string `json:"recipient"`
+	Enc       string `json:"enc"`
+}
+
+// defaultAgeIdentityFile return identity file used by SOPS by default
+func defaultAgeIdentityFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "sops", "age", "keys.txt")
+}
+
+// readDecryptedFile read file and decrypt it in memory if encrypted with age or SOPS
+func readDecryptedFile(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return decryptContents(filename, data)
+}
+
+// decryptContents return decrypted contents, or data as is if not encrypted
+func decryptContents(filename string, data []byte) ([]byte, error) {
+	if !isEncrypted(data) {
+		return data, nil
+	}
+
+	identities, err := loadAgeIdentities(ageIdentityFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var plain []byte
+	if isAgeEncrypted(data) {
+		plain, err = decryptAge(data, identities)
+	} else {
+		plain, err = decryptSops(data, identities)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", filename, err)
+	}
+	log.Debug().Msgf("decrypted %s", filename)
+
+	return plain, nil
+}
+
+// isEncrypted return true if data is encrypted with age or SOPS
+func isEncrypted(data []byte) bool {
+	return isAgeEncrypted(data) || isSopsEncrypted(data)
+}
+
+func isAgeEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(ageHeader)) || bytes.HasPrefix(bytes.TrimSpace(data), []byte(armor.Header))
+}
+
+func isSopsEncrypted(data []byte) bool {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return false
+	}
+	f := &SopsFile{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return false
+	}
+
+	return f.Sops != nil
+}
+
+// loadAgeIdentities read identities from file, or from the default identity file of SOPS
+func loadAgeIdentities(path string) ([]age.Identity, error) {
+	if path == "" {
+		path = defaultAgeIdentityFile()
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read age identity file, specify it with --age-identity: %w", err)
+	}
+	defer f.Close()
+
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid age identity file %s: %w", path, err)
+	}
+
+	return identities, nil
+}
+
+// decryptAge decrypt age encrypted data in binary or armored format
+func decryptAge(data []byte, identities []age.Identity) ([]byte, error) {
+	var in io.Reader = bytes.NewReader(data)
+	if !bytes.HasPrefix(data, []byte(ageHeader)) {
+		in = armor.NewReader(bytes.NewReader(bytes.TrimSpace(data)))
+	}
+
+	r, err := age.Decrypt(in, identities...)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(r)
+}
+
+// encryptAge encrypt data for recipients in armored format to keep it readable in diff
+func encryptAge(data []byte, recipients []string) ([]byte, error) {
+	ageRecipients := []age.Recipient{}
+	for _, recipient := range recipients {
+		r, err := age.ParseX25519Recipient(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age recipient '%s': %w", recipient, err)
+		}
+		ageRecipients = append(ageRecipients, r)
+	}
+
+	var buf bytes.Buffer
+	a := armor.NewWriter(&buf)
+	w, err := age.Encrypt(a, ageRecipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := a.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+// encryptOutput encrypt data for recipients, or return data as is if no recipient is configured
+func encryptOutput(data []byte, recipients []string) ([]byte, error) {
+	if len(recipients) == 0 {
+		return data, nil
+	}
+
+	return encryptAge(data, recipients)
+}
+
+// requireEncryption return true if file should be kept encrypted, such as *.age or existing encrypted file
+func requireEncryption(filename string) bool {
+	if filename == "" {
+		return false
+	}
+	if strings.HasSuffix(filename, ".age") {
+		return true
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return false
+	}
+
+	return isEncrypted(data)
+}
+
+// decryptSops decrypt file encrypted by SOPS as binary with data key encrypted for age
+// MAC of SOPS is verified so that tampered files are rejected
+func decryptSops(data []byte, identities []age.Identity) ([]byte, error) {
+	f := &SopsFile{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, err
+	}
+	if len(f.Sops.Age) == 0 {
+		return nil, errors.New("SOPS file is not encrypted with age")
+	}
+
+	var key []byte
+	var err error
+	for _, ageKey := range f.Sops.Age {
+		key, err = decryptAge([]byte(ageKey.Enc), identities)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("no identity matched recipients of SOPS file: %w", err)
+	}
+
+	plain, err := decryptSopsValue(f.Data, key, "data:")
+	if err != nil {
+		return nil, err
+	}
+
+	mac, err := decryptSopsValue(f.Sops.MAC, key, f.Sops.LastModified)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAC: %w", err)
+	}
+	sum := sha512.Sum512(plain)
+	expect := fmt.Sprintf("%X", sum)
+	if subtle.ConstantTimeCompare(mac, []byte(expect)) != 1 {
+		return nil, errors.New("MAC mismatch, file may be tampered")
+	}
+
+	return plain, nil
+}
+
+// decryptSopsValue decrypt value with data key, whose additional data is path of value
+func decryptSopsValue(value string, key []byte, additionalData string) ([]byte, error) {
+	matches := sopsValue.FindStringSubmatch(value)
+	if matches == nil {
+		return nil, errors.New("invalid SOPS encrypted value")
+	}
+	if matches[4] != "str" {
+		return nil, fmt.Errorf("unsupported SOPS value type '%s'", matches[4])
+	}
+	encrypted, err := base64.StdEncoding.DecodeString(matches[1])
+	if err != nil {
+		return nil, err
+	}
+	iv, err := base64.StdEncoding.DecodeString(matches[2])
+	if err != nil {
+		return nil, err
+	}
+	tag, err := base64.StdEncoding.DecodeString(matches[3])
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Open(nil, iv, append(encrypted, tag...), []byte(additionalData))
+}
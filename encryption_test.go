@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+)
+
+const testTfvars = "environment = \"production\"\ndb_password = \"supersecret\"\n"
+
+// setupAgeIdentity generate identity and use it during test
+func setupAgeIdentity(t *testing.T) *age.X25519Identity {
+	t.Helper()
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte(identity.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := ageIdentityFile
+	ageIdentityFile = path
+	t.Cleanup(func() { ageIdentityFile = previous })
+
+	return identity
+}
+
+// encryptSopsForTest build file encrypted by SOPS as binary for recipient
+func encryptSopsForTest(t *testing.T, plain []byte, recipient string, lastModified string) []byte {
+	t.Helper()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	encKey, err := encryptAge(key, []string{recipient})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mac := fmt.Sprintf("%X", sha512.Sum512(plain))
+
+	f := &SopsFile{
+		Data: encryptSopsValueForTest(t, plain, key, "data:"),
+		Sops: &SopsMetadata{
+			Age:          []*SopsAgeKey{{Recipient: recipient, Enc: string(encKey)}},
+			LastModified: lastModified,
+			MAC:          encryptSopsValueForTest(t, []byte(mac), key, lastModified),
+		},
+	}
+	data, err := json.MarshalIndent(f, "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func encryptSopsValueForTest(t *testing.T, plain []byte, key []byte, additionalData string) string {
+	t.Helper()
+
+	iv := make([]byte, 32)
+	if _, err := rand.Read(iv); err != nil {
+		t.Fatal(err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed := gcm.Seal(nil, iv, plain, []byte(additionalData))
+	encrypted, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:str]",
+		base64.StdEncoding.EncodeToString(encrypted), base64.StdEncoding.EncodeToString(iv), base64.StdEncoding.EncodeToString(tag))
+}
+
+func TestDecryptContents(t *testing.T) {
+	identity := setupAgeIdentity(t)
+	recipient := identity.Recipient().String()
+	other, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	armored, err := encryptAge([]byte(testTfvars), []string{recipient})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var binary bytes.Buffer
+	w, err := age.Encrypt(&binary, identity.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(testTfvars))
+	w.Close()
+	sops := encryptSopsForTest(t, []byte(testTfvars), recipient, "2024-01-02T03:04:05Z")
+	otherArmored, err := encryptAge([]byte(testTfvars), []string{other.Recipient().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name      string
+		data      []byte
+		wantErr   bool
+		expectErr string
+	}{
+		{
+			name: "plaintext as is",
+			data: []byte(testTfvars),
+		},
+		{
+			name: "armored age file",
+			data: armored,
+		},
+		{
+			name: "binary age file",
+			data: binary.Bytes(),
+		},
+		{
+			name: "SOPS file encrypted with age",
+			data: sops,
+		},
+		{
+			name:      "reject tampered SOPS file",
+			data:      bytes.Replace(sops, []byte("2024-01-02T03:04:05Z"), []byte("2024-01-02T03:04:06Z"), 1),
+			wantErr:   true,
+			expectErr: "invalid MAC",
+		},
+		{
+			name:      "age file for other recipient",
+			data:      otherArmored,
+			wantErr:   true,
+			expectErr: "failed to decrypt secret.tfvars",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := decryptContents("secret.tfvars", tt.data)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expect '%s' error, got no error", tt.expectErr)
+				} else if !strings.Contains(err.Error(), tt.expectErr) {
+					t.Errorf("expect '%s' error, got '%s'", tt.expectErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+			if string(actual) != testTfvars {
+				t.Errorf("expect '%s', got '%s'", testTfvars, actual)
+			}
+		})
+	}
+}
+
+func TestNewTfvarsFileEncrypted(t *testing.T) {
+	identity := setupAgeIdentity(t)
+	encrypted, err := encryptAge([]byte(testTfvars), []string{identity.Recipient().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(t.TempDir(), "secret.tfvars.age")
+	if err := os.WriteFile(filename, encrypted, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	vf, err := NewTfvarsFile(filename)
+
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	if len(vf.vars) != 2 || vf.vars[1].Key != "db_password" || vf.vars[1].Value != "supersecret" {
+		t.Errorf("unexpected variables '%s'", vf.BuildHCLFileString())
+	}
+	if string(vf.vardata) != testTfvars {
+		t.Errorf("expect decrypted contents, got '%s'", vf.vardata)
+	}
+}
+
+func TestEncryptOutput(t *testing.T) {
+	identity := setupAgeIdentity(t)
+
+	plain, err := encryptOutput([]byte(testTfvars), nil)
+	if err != nil || string(plain) != testTfvars {
+		t.Errorf("expect data as is without recipients, got '%s' (%v)", plain, err)
+	}
+
+	encrypted, err := encryptOutput([]byte(testTfvars), []string{identity.Recipient().String()})
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	if !isEncrypted(encrypted) || bytes.Contains(encrypted, []byte("supersecret")) {
+		t.Errorf("expect encrypted contents, got '%s'", encrypted)
+	}
+	decrypted, err := decryptContents("terraform.tfvars", encrypted)
+	if err != nil || string(decrypted) != testTfvars {
+		t.Errorf("expect '%s', got '%s' (%v)", testTfvars, decrypted, err)
+	}
+
+	if _, err := encryptOutput([]byte(testTfvars), []string{"invalid"}); err == nil {
+		t.Errorf("expect error for invalid recipient, got no error")
+	}
+}
+
+func TestRequireEncryption(t *testing.T) {
+	identity := setupAgeIdentity(t)
+	dir := t.TempDir()
+	encrypted, err := encryptAge([]byte(testTfvars), []string{identity.Recipient().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"plain.tfvars":     []byte(testTfvars),
+		"encrypted.tfvars": encrypted,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name     string
+		filename string
+		expect   bool
+	}{
+		{name: "not specified", filename: "", expect: false},
+		{name: "plaintext file", filename: filepath.Join(dir, "plain.tfvars"), expect: false},
+		{name: "encrypted file", filename: filepath.Join(dir, "encrypted.tfvars"), expect: true},
+		{name: "age extension not exist", filename: filepath.Join(dir, "new.tfvars.age"), expect: true},
+		{name: "plaintext file not exist", filename: filepath.Join(dir, "new.tfvars"), expect: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := requireEncryption(tt.filename)
+
+			if actual != tt.expect {
+				t.Errorf("expect '%v', got '%v'", tt.expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+)
+
+const defaultFileMode fs.FileMode = 0644
+
+// writeFileAtomic replace contents of filename with data through temporary file
+// file mode of existing file is preserved, and previous contents is saved as filename.bak if backup is enabled
+func writeFileAtomic(filename string, data []byte, backup bool) error {
+	mode := defaultFileMode
+	prev, err := os.ReadFile(filename)
+	if err == nil {
+		if bytes.Equal(prev, data) {
+			log.Debug().Msgf("%s is up to date", filename)
+			return nil
+		}
+
+		info, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+		mode = info.Mode().Perm()
+
+		if backup {
+			err = os.WriteFile(filename+".bak", prev, mode)
+			if err != nil {
+				log.Error().Err(err).Msgf("cannot write backup file: %s.bak", filename)
+				return err
+			}
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		log.Error().Err(err).Msgf("cannot read file: %s", filename)
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		log.Error().Err(err).Msgf("cannot create temporary file for %s", filename)
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpName, filename)
+}
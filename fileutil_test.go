@@ -0,0 +1,104 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	cases := []struct {
+		name         string
+		prev         []byte
+		prevMode     fs.FileMode
+		data         []byte
+		backup       bool
+		expectMode   fs.FileMode
+		expectBackup []byte
+	}{
+		{
+			name:       "create new file",
+			data:       []byte("environment = \"test\"\n"),
+			backup:     true,
+			expectMode: 0644,
+		},
+		{
+			name:         "replace file and keep backup",
+			prev:         []byte("environment = \"development\"\n"),
+			prevMode:     0600,
+			data:         []byte("environment = \"test\"\n"),
+			backup:       true,
+			expectMode:   0600,
+			expectBackup: []byte("environment = \"development\"\n"),
+		},
+		{
+			name:       "replace file without backup",
+			prev:       []byte("environment = \"development\"\n"),
+			prevMode:   0640,
+			data:       []byte("environment = \"test\"\n"),
+			backup:     false,
+			expectMode: 0640,
+		},
+		{
+			name:       "do nothing if contents not changed",
+			prev:       []byte("environment = \"test\"\n"),
+			prevMode:   0600,
+			data:       []byte("environment = \"test\"\n"),
+			backup:     true,
+			expectMode: 0600,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filename := filepath.Join(dir, "terraform.tfvars")
+			if tt.prev != nil {
+				if err := os.WriteFile(filename, tt.prev, tt.prevMode); err != nil {
+					t.Fatal(err)
+				}
+				// ignore umask
+				if err := os.Chmod(filename, tt.prevMode); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := writeFileAtomic(filename, tt.data, tt.backup)
+			if err != nil {
+				t.Fatalf("expect no error, got error: %v", err)
+			}
+
+			actual, err := os.ReadFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != string(tt.data) {
+				t.Errorf("expect '%s', got '%s'", tt.data, actual)
+			}
+			info, err := os.Stat(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != tt.expectMode {
+				t.Errorf("expect mode %v, got %v", tt.expectMode, info.Mode().Perm())
+			}
+
+			backup, err := os.ReadFile(filename + ".bak")
+			if tt.expectBackup == nil {
+				if err == nil {
+					t.Errorf("expect no backup file, got '%s'", backup)
+				}
+			} else if string(backup) != string(tt.expectBackup) {
+				t.Errorf("expect backup '%s', got '%s'", tt.expectBackup, backup)
+			}
+
+			entries, _ := os.ReadDir(dir)
+			for _, entry := range entries {
+				if entry.Name() != "terraform.tfvars" && entry.Name() != "terraform.tfvars.bak" {
+					t.Errorf("expect temporary file removed, got '%s'", entry.Name())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/urfave/cli/v2"
+)
+
+// VariableFilter select variables by key, glob or regular expression pattern, category and attributes
+type VariableFilter struct {
+	keys          []string
+	includes      []string
+	excludes      []string
+	regexps       []string
+	category      string
+	sensitiveOnly bool
+	noSensitive   bool
+	hclOnly       bool
+}
+
+// NewVariableFilter build filter from command flags and exact keys to select
+// return nil if no filter specified, which matches all variables
+func NewVariableFilter(c *cli.Context, keys []string) *VariableFilter {
+	filter := &VariableFilter{}
+
+	filter.keys = keys
+	filter.includes = c.StringSlice("include")
+	filter.excludes = c.StringSlice("exclude")
+	filter.regexps = c.StringSlice("regex")
+	filter.category = c.String("category")
+	filter.sensitiveOnly = c.Bool("sensitive-only")
+	filter.noSensitive = c.Bool("no-sensitive")
+	filter.hclOnly = c.Bool("hcl-only")
+
+	if len(filter.keys) == 0 && len(filter.includes) == 0 && len(filter.excludes) == 0 && len(filter.regexps) == 0 &&
+		filter.category == "" && !filter.sensitiveOnly && !filter.noSensitive && !filter.hclOnly {
+		return nil
+	}
+
+	return filter
+}
+
+// Validate check glob and regular expression patterns
+func (f *VariableFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	for _, pattern := range append(append([]string{}, f.includes...), f.excludes...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+		}
+	}
+	for _, pattern := range f.regexps {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid regex '%s': %w", pattern, err)
+		}
+	}
+	if f.sensitiveOnly && f.noSensitive {
+		return errors.New("--sensitive-only and --no-sensitive cannot be specified together")
+	}
+
+	return nil
+}
+
+// Match return true if variable is selected by filter
+func (f *VariableFilter) Match(v *tfe.Variable) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.category != "" && string(variableCategory(v)) != f.category {
+		return false
+	}
+	if (f.sensitiveOnly && !v.Sensitive) || (f.noSensitive && v.Sensitive) {
+		return false
+	}
+	if f.hclOnly && !v.HCL {
+		return false
+	}
+
+	if len(f.keys) != 0 || len(f.includes) != 0 || len(f.regexps) != 0 {
+		selected := false
+		for _, key := range f.keys {
+			if key == v.Key {
+				selected = true
+			}
+		}
+		for _, pattern := range f.includes {
+			if matched, _ := path.Match(pattern, v.Key); matched {
+				selected = true
+			}
+		}
+		for _, pattern := range f.regexps {
+			if matched, _ := regexp.MatchString(pattern, v.Key); matched {
+				selected = true
+			}
+		}
+		if !selected {
+			return false
+		}
+	}
+
+	for _, pattern := range f.excludes {
+		if matched, _ := path.Match(pattern, v.Key); matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter return variables selected by filter
+func (f *VariableFilter) Filter(vars []*tfe.Variable) []*tfe.Variable {
+	if f == nil {
+		return vars
+	}
+
+	filteredVars := []*tfe.Variable{}
+	for _, v := range vars {
+		if f.Match(v) {
+			filteredVars = append(filteredVars, v)
+		}
+	}
+
+	return filteredVars
+}
+
+// hasSelector return true if filter selects variables by key or category
+// filters only excluding variables, such as --exclude and --no-sensitive, select almost all variables
+func (f *VariableFilter) hasSelector() bool {
+	return f != nil && (len(f.keys) != 0 || len(f.includes) != 0 || len(f.regexps) != 0 || f.category != "")
+}
+
+// requireEnv return true if filter selects env category variables explicitly
+func (f *VariableFilter) requireEnv() bool {
+	return f != nil && f.category == string(tfe.CategoryEnv)
+}
+
+// variableCategory return category of variable, treating unspecified category as terraform
+func variableCategory(v *tfe.Variable) tfe.CategoryType {
+	if v.Category == "" {
+		return tfe.CategoryTerraform
+	}
+
+	return v.Category
+}
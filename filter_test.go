@@ -0,0 +1,150 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	tfe "github.com/hashicorp/go-tfe"
+)
+
+func TestVariableFilter_Filter(t *testing.T) {
+	vars := []*tfe.Variable{
+		{Key: "environment", Value: "test"},
+		{Key: "db_host", Value: "localhost", Category: tfe.CategoryTerraform},
+		{Key: "db_password", Sensitive: true, Category: tfe.CategoryTerraform},
+		{Key: "DB_URL", Value: "postgres://localhost", Category: tfe.CategoryEnv},
+		{Key: "db_replicas", Value: "[\"a\"]", Category: tfe.CategoryTerraform, HCL: true},
+	}
+
+	cases := []struct {
+		name   string
+		filter *VariableFilter
+		expect []string
+	}{
+		{
+			name:   "nil filter select all variables",
+			filter: nil,
+			expect: []string{"environment", "db_host", "db_password", "DB_URL", "db_replicas"},
+		},
+		{
+			name:   "select exact keys",
+			filter: &VariableFilter{keys: []string{"environment", "db_host"}},
+			expect: []string{"environment", "db_host"},
+		},
+		{
+			name:   "select glob pattern",
+			filter: &VariableFilter{includes: []string{"db_*"}},
+			expect: []string{"db_host", "db_password", "db_replicas"},
+		},
+		{
+			name:   "union of keys and patterns",
+			filter: &VariableFilter{keys: []string{"environment"}, includes: []string{"db_*"}},
+			expect: []string{"environment", "db_host", "db_password", "db_replicas"},
+		},
+		{
+			name:   "exclude glob pattern",
+			filter: &VariableFilter{includes: []string{"db_*"}, excludes: []string{"*password*"}},
+			expect: []string{"db_host", "db_replicas"},
+		},
+		{
+			name:   "exclude without include",
+			filter: &VariableFilter{excludes: []string{"db_*"}},
+			expect: []string{"environment", "DB_URL"},
+		},
+		{
+			name:   "select terraform category including unspecified category",
+			filter: &VariableFilter{category: "terraform"},
+			expect: []string{"environment", "db_host", "db_password", "db_replicas"},
+		},
+		{
+			name:   "select env category",
+			filter: &VariableFilter{category: "env"},
+			expect: []string{"DB_URL"},
+		},
+		{
+			name:   "select regular expression case insensitively",
+			filter: &VariableFilter{regexps: []string{"(?i)^db_(host|url)$"}},
+			expect: []string{"db_host", "DB_URL"},
+		},
+		{
+			name:   "select only sensitive variables",
+			filter: &VariableFilter{sensitiveOnly: true},
+			expect: []string{"db_password"},
+		},
+		{
+			name:   "ignore sensitive variables",
+			filter: &VariableFilter{includes: []string{"db_*"}, noSensitive: true},
+			expect: []string{"db_host", "db_replicas"},
+		},
+		{
+			name:   "select only HCL variables",
+			filter: &VariableFilter{hclOnly: true},
+			expect: []string{"db_replicas"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := []string{}
+			for _, v := range tt.filter.Filter(vars) {
+				actual = append(actual, v.Key)
+			}
+
+			if !reflect.DeepEqual(tt.expect, actual) {
+				t.Errorf("expect '%v', got '%v'", tt.expect, actual)
+			}
+		})
+	}
+}
+
+func TestVariableFilter_Validate(t *testing.T) {
+	cases := []struct {
+		name    string
+		filter  *VariableFilter
+		wantErr bool
+	}{
+		{
+			name:    "nil filter",
+			filter:  nil,
+			wantErr: false,
+		},
+		{
+			name:    "valid patterns",
+			filter:  &VariableFilter{includes: []string{"db_*"}, excludes: []string{"db_[ab]?"}},
+			wantErr: false,
+		},
+		{
+			name:    "invalid include pattern",
+			filter:  &VariableFilter{includes: []string{"db_["}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid exclude pattern",
+			filter:  &VariableFilter{excludes: []string{"[-"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid regex",
+			filter:  &VariableFilter{regexps: []string{"db_("}},
+			wantErr: true,
+		},
+		{
+			name:    "sensitive only and no sensitive",
+			filter:  &VariableFilter{sensitiveOnly: true, noSensitive: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expect error, got no error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+		})
+	}
+}
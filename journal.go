@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	JOURNAL_TARGET_WORKSPACE    = "workspace"
+	JOURNAL_TARGET_VARIABLE_SET = "variable-set"
+)
+
+// changeJournal records changes applied to Terraform Cloud, and nothing is recorded if nil
+var changeJournal *Journal
+
+// Journal is append-only JSONL file of changes applied by commands
+type Journal struct {
+	path    string
+	command string
+}
+
+// JournalEntry is changes applied to a workspace or variable set by a command
+type JournalEntry struct {
+	ID         int                 `json:"id"`
+	Time       time.Time           `json:"time"`
+	Command    string              `json:"command"`
+	Target     string              `json:"target"`
+	TargetID   string              `json:"target_id"`
+	Operations []*JournalOperation `json:"operations"`
+}
+
+// JournalOperation is an operation applied to a variable
+type JournalOperation struct {
+	Operation  string           `json:"operation"`
+	Key        string           `json:"key"`
+	VariableID string           `json:"variable_id,omitempty"`
+	Before     *JournalVariable `json:"before,omitempty"`
+	After      *JournalVariable `json:"after,omitempty"`
+}
+
+// JournalVariable is attributes of variable, whose value is recorded unless sensitive
+type JournalVariable struct {
+	Value       *string          `json:"value,omitempty"`
+	Description string           `json:"description"`
+	Category    tfe.CategoryType `json:"category"`
+	HCL         bool             `json:"hcl"`
+	Sensitive   bool             `json:"sensitive"`
+}
+
+func NewJournal(path string, command string) *Journal {
+	return &Journal{path: path, command: command}
+}
+
+// defaultJournalPath return path of journal under user config directory
+func defaultJournalPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "tfcvars", "journal.jsonl"), nil
+}
+
+// setupJournal open journal for commands which change variables
+// journal is disabled if its path cannot be determined, since recording must not block changes
+func setupJournal(c *cli.Context) error {
+	path := c.String("journal")
+	if path == "" {
+		var err error
+		path, err = defaultJournalPath()
+		if err != nil {
+			log.Warn().Err(err).Msg("change journal is disabled")
+			return nil
+		}
+	}
+	changeJournal = NewJournal(path, c.Args().First())
+
+	return nil
+}
+
+// newJournalOperation build record of applied operation
+func newJournalOperation(variable *PushVariable, variableId string) *JournalOperation {
+	return &JournalOperation{
+		Operation:  variable.operation,
+		Key:        variable.key(),
+		VariableID: variableId,
+		Before:     newJournalVariable(variable.previous, true),
+		After:      newJournalVariable(variable.result(), false),
+	}
+}
+
+// newJournalVariable return attributes of variable to be recorded, with value if withValue and not sensitive
+func newJournalVariable(v *tfe.Variable, withValue bool) *JournalVariable {
+	if v == nil {
+		return nil
+	}
+
+	jv := &JournalVariable{
+		Description: v.Description,
+		Category:    variableCategory(v),
+		HCL:         v.HCL,
+		Sensitive:   v.Sensitive,
+	}
+	if withValue && !v.Sensitive {
+		jv.Value = tfe.String(v.Value)
+	}
+
+	return jv
+}
+
+// record append applied operations as an entry
+func (j *Journal) record(target string, targetId string, operations []*JournalOperation) error {
+	if j == nil || len(operations) == 0 {
+		return nil
+	}
+
+	entries, err := j.entries()
+	if err != nil {
+		return err
+	}
+	entry := &JournalEntry{
+		ID:         1,
+		Time:       time.Now().UTC(),
+		Command:    j.command,
+		Target:     target,
+		TargetID:   targetId,
+		Operations: operations,
+	}
+	if len(entries) != 0 {
+		entry.ID = entries[len(entries)-1].ID + 1
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// entries return all entries in journal, or empty if journal does not exist
+func (j *Journal) entries() ([]*JournalEntry, error) {
+	entries := []*JournalEntry{}
+
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		entry := &JournalEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, fmt.Errorf("invalid journal entry at %s:%d: %w", j.path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// entry return entry of id
+func (j *Journal) entry(id int) (*JournalEntry, error) {
+	entries, err := j.entries()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("change %d not found in journal", id)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-tfe/mocks"
+)
+
+func TestJournalRecord(t *testing.T) {
+	journal := NewJournal(filepath.Join(t.TempDir(), "tfcvars", "journal.jsonl"), "push")
+
+	entries, err := journal.entries()
+	if err != nil {
+		t.Fatalf("expect no error for missing journal, got error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expect no entries, got %d", len(entries))
+	}
+
+	operations := []*JournalOperation{
+		{Operation: PUSH_OPERATION_CREATE, Key: "environment", VariableID: "v-environment"},
+	}
+	if err := journal.record(JOURNAL_TARGET_WORKSPACE, "ws-test", operations); err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	if err := journal.record(JOURNAL_TARGET_VARIABLE_SET, "varset-test", operations); err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	if err := journal.record(JOURNAL_TARGET_WORKSPACE, "ws-test", []*JournalOperation{}); err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+
+	entries, err = journal.entries()
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, got %d", len(entries))
+	}
+	if entries[0].ID != 1 || entries[1].ID != 2 {
+		t.Errorf("expect sequential IDs, got %d and %d", entries[0].ID, entries[1].ID)
+	}
+	if entries[1].Command != "push" || entries[1].Target != JOURNAL_TARGET_VARIABLE_SET || entries[1].TargetID != "varset-test" {
+		t.Errorf("unexpected entry '%+v'", entries[1])
+	}
+	if !reflect.DeepEqual(operations, entries[1].Operations) {
+		t.Errorf("expect '%+v', got '%+v'", operations, entries[1].Operations)
+	}
+
+	info, err := os.Stat(journal.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expect file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	if _, err := journal.entry(3); err == nil {
+		t.Errorf("expect error for missing entry, got no error")
+	}
+}
+
+func TestApplyPushVariablesRecordJournal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVariables := mocks.NewMockVariables(ctrl)
+
+	journal := NewJournal(filepath.Join(t.TempDir(), "journal.jsonl"), "push")
+	changeJournal = journal
+	defer func() { changeJournal = nil }()
+
+	previousEnvironment := &tfe.Variable{ID: "v-environment", Key: "environment", Value: "staging", Category: tfe.CategoryTerraform}
+	previousPassword := &tfe.Variable{ID: "v-db_password", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true}
+	variables := []*PushVariable{
+		{
+			operation: PUSH_OPERATION_CREATE,
+			createOption: tfe.VariableCreateOptions{
+				Key:      tfe.String("zones"),
+				Value:    tfe.String(`["a"]`),
+				Category: tfe.Category(tfe.CategoryTerraform),
+				HCL:      tfe.Bool(true),
+			},
+		},
+		{
+			operation: PUSH_OPERATION_UPDATE,
+			id:        "v-environment",
+			previous:  previousEnvironment,
+			updateOption: tfe.VariableUpdateOptions{
+				Key:         tfe.String("environment"),
+				Value:       tfe.String("production"),
+				Description: tfe.String("env name"),
+			},
+		},
+		{operation: PUSH_OPERATION_DELETE, id: "v-db_password", previous: previousPassword},
+		{operation: PUSH_OPERATION_DELETE, id: "v-port", previous: &tfe.Variable{ID: "v-port", Key: "port"}},
+	}
+	mockVariables.EXPECT().Create(gomock.Any(), "ws-test", gomock.Any()).Return(&tfe.Variable{ID: "v-zones"}, nil)
+	mockVariables.EXPECT().Update(gomock.Any(), "ws-test", "v-environment", gomock.Any()).Return(&tfe.Variable{}, nil)
+	mockVariables.EXPECT().Delete(gomock.Any(), "ws-test", "v-db_password").Return(nil)
+	mockVariables.EXPECT().Delete(gomock.Any(), "ws-test", "v-port").Return(errors.New("permission denied"))
+
+	err := applyPushVariables(context.TODO(), "ws-test", mockVariables, variables, new(bytes.Buffer))
+	if err == nil {
+		t.Errorf("expect error of failed operation, got no error")
+	}
+
+	entries, err := journal.entries()
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, got %d", len(entries))
+	}
+	expect := []*JournalOperation{
+		{
+			Operation:  PUSH_OPERATION_CREATE,
+			Key:        "zones",
+			VariableID: "v-zones",
+			After:      &JournalVariable{Category: tfe.CategoryTerraform, HCL: true},
+		},
+		{
+			Operation:  PUSH_OPERATION_UPDATE,
+			Key:        "environment",
+			VariableID: "v-environment",
+			Before:     &JournalVariable{Value: tfe.String("staging"), Category: tfe.CategoryTerraform},
+			After:      &JournalVariable{Description: "env name", Category: tfe.CategoryTerraform},
+		},
+		{
+			Operation:  PUSH_OPERATION_DELETE,
+			Key:        "db_password",
+			VariableID: "v-db_password",
+			Before:     &JournalVariable{Category: tfe.CategoryTerraform, Sensitive: true},
+		},
+	}
+	if !reflect.DeepEqual(expect, entries[0].Operations) {
+		t.Errorf("expect '%+v', got '%+v'", expect, entries[0].Operations)
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	LINT_SEVERITY_ERROR   = "error"
+	LINT_SEVERITY_WARNING = "warning"
+)
+
+const (
+	LINT_RULE_SYNTAX                 = "syntax"
+	LINT_RULE_DUPLICATE_KEY          = "duplicate-key"
+	LINT_RULE_UNSUPPORTED_BLOCK      = "unsupported-block"
+	LINT_RULE_INVALID_IDENTIFIER     = "invalid-identifier"
+	LINT_RULE_UNSUPPORTED_EXPRESSION = "unsupported-expression"
+	LINT_RULE_UNDECLARED_VARIABLE    = "undeclared-variable"
+	LINT_RULE_PLAINTEXT_SECRET       = "plaintext-secret"
+	LINT_RULE_REMOTE_SENSITIVE       = "remote-sensitive"
+)
+
+// LintRule describe a rule checked by lint command
+type LintRule struct {
+	ID          string
+	Severity    string
+	Description string
+}
+
+// lintRules is all rules in the order of documentation
+var lintRules = []*LintRule{
+	{ID: LINT_RULE_SYNTAX, Severity: LINT_SEVERITY_ERROR, Description: "tfvars file must be valid HCL"},
+	{ID: LINT_RULE_DUPLICATE_KEY, Severity: LINT_SEVERITY_ERROR, Description: "each variable must be set only once"},
+	{ID: LINT_RULE_UNSUPPORTED_BLOCK, Severity: LINT_SEVERITY_ERROR, Description: "blocks are not allowed in tfvars file"},
+	{ID: LINT_RULE_INVALID_IDENTIFIER, Severity: LINT_SEVERITY_ERROR, Description: "variable name must be a valid identifier and not reserved by terraform"},
+	{ID: LINT_RULE_UNSUPPORTED_EXPRESSION, Severity: LINT_SEVERITY_ERROR, Description: "values must be literals without references or function calls"},
+	{ID: LINT_RULE_UNDECLARED_VARIABLE, Severity: LINT_SEVERITY_WARNING, Description: "variable should be declared in terraform configuration"},
+	{ID: LINT_RULE_PLAINTEXT_SECRET, Severity: LINT_SEVERITY_WARNING, Description: "values which look secret should not be kept in plaintext"},
+	{ID: LINT_RULE_REMOTE_SENSITIVE, Severity: LINT_SEVERITY_ERROR, Description: "values of variables sensitive in Terraform Cloud must not be kept in plaintext"},
+}
+
+// reservedVariableNames cannot be used as variable names in terraform
+var reservedVariableNames = map[string]bool{
+	"source":     true,
+	"version":    true,
+	"providers":  true,
+	"count":      true,
+	"for_each":   true,
+	"lifecycle":  true,
+	"depends_on": true,
+	"locals":     true,
+}
+
+// LintDiagnostic is a problem found in tfvars file
+type LintDiagnostic struct {
+	Rule     string    `json:"rule"`
+	Severity string    `json:"severity"`
+	Message  string    `json:"message"`
+	Range    LintRange `json:"range"`
+}
+
+// LintRange is location of diagnostic, whose lines and columns start at 1
+type LintRange struct {
+	Filename    string `json:"filename"`
+	StartLine   int    `json:"start_line"`
+	StartColumn int    `json:"start_column"`
+	EndLine     int    `json:"end_line"`
+	EndColumn   int    `json:"end_column"`
+}
+
+func newLintRange(r hcl.Range) LintRange {
+	return LintRange{
+		Filename:    r.Filename,
+		StartLine:   r.Start.Line,
+		StartColumn: r.Start.Column,
+		EndLine:     r.End.Line,
+		EndColumn:   r.End.Column,
+	}
+}
+
+// String return range as file:line,column-line,column
+func (r LintRange) String() string {
+	return hcl.Range{
+		Filename: r.Filename,
+		Start:    hcl.Pos{Line: r.StartLine, Column: r.StartColumn},
+		End:      hcl.Pos{Line: r.EndLine, Column: r.EndColumn},
+	}.String()
+}
+
+// newLintDiagnostic build diagnostic with severity of rule
+func newLintDiagnostic(rule string, r hcl.Range, format string, args ...any) *LintDiagnostic {
+	severity := LINT_SEVERITY_ERROR
+	for _, lintRule := range lintRules {
+		if lintRule.ID == rule {
+			severity = lintRule.Severity
+		}
+	}
+
+	return &LintDiagnostic{
+		Rule:     rule,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+		Range:    newLintRange(r),
+	}
+}
+
+// lintFiles check tfvars files with variables declared in the same directory
+// remoteSensitive is keys of variables sensitive in Terraform Cloud, and nil disables the rule
+func lintFiles(filenames []string, remoteSensitive map[string]bool) ([]*LintDiagnostic, error) {
+	diagnostics := []*LintDiagnostic{}
+
+	for _, filename := range filenames {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		encrypted := isEncrypted(src)
+		src, err = decryptContents(filename, src)
+		if err != nil {
+			return nil, err
+		}
+		declarations, err := loadVariableDeclarations(filepath.Dir(filename))
+		if err != nil {
+			return nil, err
+		}
+		for _, diagnostic := range lintTfvars(filename, src, declarations, remoteSensitive) {
+			// values of encrypted file are not kept in plaintext
+			if encrypted && (diagnostic.Rule == LINT_RULE_PLAINTEXT_SECRET || diagnostic.Rule == LINT_RULE_REMOTE_SENSITIVE) {
+				continue
+			}
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+
+	return diagnostics, nil
+}
+
+// lintTfvars check contents of tfvars file and return diagnostics sorted by position
+// undeclared variables are reported only if any variable is declared
+func lintTfvars(filename string, src []byte, declarations map[string]*VariableDeclaration, remoteSensitive map[string]bool) []*LintDiagnostic {
+	diagnostics := []*LintDiagnostic{}
+
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	for _, diag := range diags {
+		r := hcl.Range{Filename: filename, Start: hcl.InitialPos, End: hcl.InitialPos}
+		if diag.Subject != nil {
+			r = *diag.Subject
+		}
+		if diag.Summary == "Attribute redefined" {
+			diagnostics = append(diagnostics, newLintDiagnostic(LINT_RULE_DUPLICATE_KEY, r, "%s", diag.Detail))
+			continue
+		}
+		diagnostics = append(diagnostics, newLintDiagnostic(LINT_RULE_SYNTAX, r, "%s: %s", diag.Summary, diag.Detail))
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return diagnostics
+	}
+
+	for _, block := range body.Blocks {
+		diagnostics = append(diagnostics, newLintDiagnostic(LINT_RULE_UNSUPPORTED_BLOCK, block.DefRange(), "block '%s' is not allowed in tfvars file", block.Type))
+	}
+
+	for _, attr := range body.Attributes {
+		if !hclsyntax.ValidIdentifier(attr.Name) || reservedVariableNames[attr.Name] {
+			diagnostics = append(diagnostics, newLintDiagnostic(LINT_RULE_INVALID_IDENTIFIER, attr.NameRange, "'%s' cannot be used as variable name", attr.Name))
+		}
+		if len(declarations) != 0 && declarations[attr.Name] == nil {
+			diagnostics = append(diagnostics, newLintDiagnostic(LINT_RULE_UNDECLARED_VARIABLE, attr.NameRange, "variable '%s' is not declared in terraform configuration", attr.Name))
+		}
+
+		val, valueDiags := attr.Expr.Value(nil)
+		if valueDiags.HasErrors() {
+			for _, diag := range valueDiags {
+				r := attr.Expr.Range()
+				if diag.Subject != nil {
+					r = *diag.Subject
+				}
+				diagnostics = append(diagnostics, newLintDiagnostic(LINT_RULE_UNSUPPORTED_EXPRESSION, r, "value of '%s' must be literal: %s", attr.Name, diag.Summary))
+			}
+			continue
+		}
+		if isPlaceholderValue(val) {
+			continue
+		}
+
+		if remoteSensitive[attr.Name] {
+			diagnostics = append(diagnostics, newLintDiagnostic(LINT_RULE_REMOTE_SENSITIVE, attr.SrcRange, "'%s' is sensitive in Terraform Cloud but has plaintext value", attr.Name))
+			continue
+		}
+		value := String(val)
+		if looksSecret(&tfe.Variable{Key: attr.Name, Value: value, HCL: !IsPrimitive(val)}) {
+			diagnostics = append(diagnostics, newLintDiagnostic(LINT_RULE_PLAINTEXT_SECRET, attr.SrcRange, "value of '%s' looks secret", attr.Name))
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Range.StartLine != diagnostics[j].Range.StartLine {
+			return diagnostics[i].Range.StartLine < diagnostics[j].Range.StartLine
+		}
+		return diagnostics[i].Range.StartColumn < diagnostics[j].Range.StartColumn
+	})
+
+	return diagnostics
+}
+
+// isPlaceholderValue return true if value is null or zero value, such as placeholder of sensitive variable written by pull
+func isPlaceholderValue(val cty.Value) bool {
+	if val.IsNull() || !val.IsKnown() {
+		return true
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		return val.AsString() == ""
+	case ty == cty.Number:
+		return val.Equals(cty.Zero).True()
+	case ty == cty.Bool:
+		return val.False()
+	case ty.IsTupleType(), ty.IsObjectType(), ty.IsCollectionType():
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			if !isPlaceholderValue(v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// hasLintError return true if any diagnostic is error
+func hasLintError(diagnostics []*LintDiagnostic) bool {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == LINT_SEVERITY_ERROR {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLintTfvars(t *testing.T) {
+	declarations, err := loadVariableDeclarations("testdata/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name            string
+		src             string
+		declarations    map[string]*VariableDeclaration
+		remoteSensitive map[string]bool
+		expect          []string
+	}{
+		{
+			name:         "valid tfvars",
+			src:          "db_port = 5432\ndb_hosts = [\"db1\", \"db2\"]\ndb_password = \"\"\n",
+			declarations: declarations,
+			expect:       []string{},
+		},
+		{
+			name:   "duplicate key",
+			src:    "db_port = 5432\ndb_port = 3306\n",
+			expect: []string{"duplicate-key:test.tfvars:2,1-8"},
+		},
+		{
+			name:   "syntax error",
+			src:    "db_port = \n",
+			expect: []string{"syntax:test.tfvars:1,11-2,1"},
+		},
+		{
+			name:   "block and reserved name",
+			src:    "count = 1\nvariable \"db_port\" {\n}\n",
+			expect: []string{"invalid-identifier:test.tfvars:1,1-6", "unsupported-block:test.tfvars:2,1-19"},
+		},
+		{
+			name:   "reference and function call",
+			src:    "db_port = var.port\ndb_hosts = split(\",\", \"db1,db2\")\n",
+			expect: []string{"unsupported-expression:test.tfvars:1,11-14", "unsupported-expression:test.tfvars:2,12-33"},
+		},
+		{
+			name:         "undeclared variable",
+			src:          "db_port = 5432\nregion = \"ap-northeast-1\"\n",
+			declarations: declarations,
+			expect:       []string{"undeclared-variable:test.tfvars:2,1-7"},
+		},
+		{
+			name:   "plaintext secret",
+			src:    "db_password = \"p@ssw0rd\"\ntoken_id = \"ghp4f7Kx9Qm2Lz8Rt6Vb1Nc3\"\nenvironment = \"production\"\n",
+			expect: []string{"plaintext-secret:test.tfvars:1,1-25", "plaintext-secret:test.tfvars:2,1-38"},
+		},
+		{
+			name:            "remote sensitive",
+			src:             "db_port = 5432\napi_endpoint = \"https://example.com\"\nempty = \"\"\n",
+			remoteSensitive: map[string]bool{"api_endpoint": true, "empty": true},
+			expect:          []string{"remote-sensitive:test.tfvars:2,1-37"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := lintTfvars("test.tfvars", []byte(tt.src), tt.declarations, tt.remoteSensitive)
+
+			actual := []string{}
+			for _, diagnostic := range diagnostics {
+				actual = append(actual, diagnostic.Rule+":"+diagnostic.Range.String())
+			}
+			if !reflect.DeepEqual(tt.expect, actual) {
+				t.Errorf("expect '%v', got '%v'", tt.expect, actual)
+			}
+		})
+	}
+}
+
+func TestIsPlaceholderValue(t *testing.T) {
+	cases := []struct {
+		name   string
+		value  string
+		expect bool
+	}{
+		{name: "empty string", value: `""`, expect: true},
+		{name: "null", value: `null`, expect: true},
+		{name: "zero", value: `0`, expect: true},
+		{name: "false", value: `false`, expect: true},
+		{name: "object of empty values", value: `{ password = "" }`, expect: true},
+		{name: "string", value: `"secret"`, expect: false},
+		{name: "list with value", value: `["", "secret"]`, expect: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := isPlaceholderValue(CtyValue(tt.value))
+
+			if actual != tt.expect {
+				t.Errorf("expect '%v', got '%v'", tt.expect, actual)
+			}
+		})
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"log"
 	"os"
 	"runtime/debug"
+	"time"
 
+	tfe "github.com/hashicorp/go-tfe"
 	"github.com/urfave/cli/v2"
 )
 
@@ -40,7 +42,19 @@ func main() {
 				EnvVars:     []string{"TFCVARS_WORKSPACE"},
 				Destination: &workspaceName,
 			},
+			&cli.StringFlag{
+				Name:    "journal",
+				Usage:   "Path of journal to record changes (default: tfcvars/journal.jsonl in user config directory)",
+				EnvVars: []string{"TFCVARS_JOURNAL"},
+			},
+			&cli.StringFlag{
+				Name:        "age-identity",
+				Usage:       "Path of age identity file to decrypt encrypted tfvars files (default: sops/age/keys.txt in user config directory)",
+				EnvVars:     []string{"TFCVARS_AGE_IDENTITY", "SOPS_AGE_KEY_FILE"},
+				Destination: &ageIdentityFile,
+			},
 		},
+		Before: setupJournal,
 		Commands: []*cli.Command{
 			{
 				Name:  "help",
@@ -76,6 +90,57 @@ func main() {
 				Flags:  removeFlags(),
 				Usage:  "remove Terraform Cloud variables",
 			},
+			{
+				Name:   "copy",
+				Action: Copy,
+				Flags:  copyFlags(),
+				Usage:  "copy Terraform Cloud variables between workspaces",
+			},
+			{
+				Name:   "export",
+				Action: Export,
+				Flags:  exportFlags(),
+				Usage:  "export variables of all workspaces in organization into directory",
+			},
+			{
+				Name:      "restore",
+				Action:    Restore,
+				Flags:     restoreFlags(),
+				Usage:     "restore Terraform Cloud variables from directory written by export",
+				ArgsUsage: "DIR",
+			},
+			{
+				Name:   "watch",
+				Action: Watch,
+				Flags:  watchFlags(),
+				Usage:  "watch drift between local tfvars and Terraform Cloud variables periodically",
+			},
+			{
+				Name:   "history",
+				Action: History,
+				Flags:  historyFlags(),
+				Usage:  "show changes recorded in journal",
+			},
+			{
+				Name:   "undo",
+				Action: Undo,
+				Flags:  undoFlags(),
+				Usage:  "revert changes recorded in journal",
+			},
+			{
+				Name:      "lint",
+				Action:    Lint,
+				Flags:     lintFlags(),
+				ArgsUsage: "[FILE...]",
+				Usage:     "check tfvars files for problems",
+			},
+			{
+				Name:      "guard",
+				Action:    Guard,
+				Flags:     guardFlags(),
+				ArgsUsage: "[FILE...]",
+				Usage:     "check tfvars files tracked by git do not have values of sensitive variables",
+			},
 		},
 		Version: versionFormatter(getVersion(), getRevision()),
 	}
@@ -90,7 +155,7 @@ func main() {
 }
 
 func showFlags() []cli.Flag {
-	return []cli.Flag{
+	flags := []cli.Flag{
 		&cli.BoolFlag{
 			Name:  "local",
 			Usage: "show local variables",
@@ -101,9 +166,9 @@ func showFlags() []cli.Flag {
 			Usage: "Input filename to read for local variable",
 			Value: "terraform.tfvars",
 		},
-		&cli.StringFlag{
+		&cli.StringSliceFlag{
 			Name:  "variable",
-			Usage: "Show specified variable",
+			Usage: "Show specified variable (can be specified multiple times)",
 		},
 		&cli.BoolFlag{
 			Name:  "include-env",
@@ -119,15 +184,71 @@ func showFlags() []cli.Flag {
 			Name:  "format",
 			Usage: "format to display variables",
 			Value: &FormatType{
-				Enum:    []string{"detail", "tfvars", "table"},
+				Enum:    []string{"detail", "tfvars", "dotenv", "export", "json", "yaml", "csv", "table", "template"},
 				Default: "detail",
 			},
 		},
+		&cli.StringFlag{
+			Name:  "template",
+			Usage: "go template to display variables with template format",
+		},
+		&cli.StringFlag{
+			Name:  "template-file",
+			Usage: "file of go template to display variables with template format",
+		},
+		&cli.GenericFlag{
+			Name:  "sensitive",
+			Usage: "how to write sensitive variables (comment, omit, keep-local, placeholder)",
+			Value: &FormatType{
+				Enum:    []string{SENSITIVE_COMMENT, SENSITIVE_OMIT, SENSITIVE_KEEP_LOCAL, SENSITIVE_PLACEHOLDER},
+				Default: SENSITIVE_COMMENT,
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "sensitive-only",
+			Usage: "Select only sensitive variables",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "no-sensitive",
+			Usage: "Ignore sensitive variables",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "reveal",
+			Usage: "show values which look secret such as password and token without masking",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "hcl-only",
+			Usage: "Select only HCL variables",
+			Value: false,
+		},
+		&cli.GenericFlag{
+			Name:  "source",
+			Usage: "Select variables defined in source (workspace, varset)",
+			Value: &FormatType{
+				Enum: []string{SOURCE_WORKSPACE, SOURCE_VARSET},
+			},
+		},
+		&cli.GenericFlag{
+			Name:  "sort",
+			Usage: "Sort variables by key, category or source",
+			Value: &FormatType{
+				Enum: []string{"key", "category", "source"},
+			},
+		},
+		&cli.StringSliceFlag{
+			Name:  "columns",
+			Usage: "Columns of table format (id, key, value, sensitive, description, category, hcl, source, variable_set)",
+		},
 	}
+
+	return append(flags, filterFlags()...)
 }
 
 func pullFlags() []cli.Flag {
-	return []cli.Flag{
+	flags := []cli.Flag{
 		&cli.StringFlag{
 			Name:  "var-file",
 			Usage: "Output filename to write var-file",
@@ -143,6 +264,20 @@ func pullFlags() []cli.Flag {
 			Usage: "merge variables into existing vars file",
 			Value: false,
 		},
+		&cli.StringFlag{
+			Name:  "env-file",
+			Usage: "Output filename to write env Category variables in dotenv format",
+		},
+		&cli.BoolFlag{
+			Name:  "check",
+			Usage: "report whether local files would be changed without writing them, exit with 2 if changed",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "no-backup",
+			Usage: "do not keep previous contents as .bak file",
+			Value: false,
+		},
 		&cli.BoolFlag{
 			Name:  "include-env",
 			Usage: "include env Category variables",
@@ -153,35 +288,67 @@ func pullFlags() []cli.Flag {
 			Usage: "include Variable Set variables",
 			Value: false,
 		},
+		&cli.GenericFlag{
+			Name:  "sensitive",
+			Usage: "how to write sensitive variables (comment, omit, keep-local, placeholder)",
+			Value: &FormatType{
+				Enum:    []string{SENSITIVE_COMMENT, SENSITIVE_OMIT, SENSITIVE_KEEP_LOCAL, SENSITIVE_PLACEHOLDER},
+				Default: SENSITIVE_COMMENT,
+			},
+		},
+		&cli.StringSliceFlag{
+			Name:  "variable",
+			Usage: "Pull specified variable (can be specified multiple times)",
+		},
+		&cli.StringSliceFlag{
+			Name:    "age-recipient",
+			Usage:   "Encrypt written files with age for recipient (can be specified multiple times)",
+			EnvVars: []string{"TFCVARS_AGE_RECIPIENTS"},
+		},
 	}
+
+	return append(flags, filterFlags()...)
 }
 
 func pushFlags() []cli.Flag {
-	return []cli.Flag{
+	flags := []cli.Flag{
 		&cli.StringFlag{
 			Name:  "var-file",
 			Usage: "Input filename to push variables",
 			Value: "terraform.tfvars",
 		},
-		&cli.StringFlag{
+		&cli.StringSliceFlag{
 			Name:  "variable",
-			Usage: "Crate or Update Specified variable",
+			Usage: "Push specified variable, or value of KEY=VALUE (can be specified multiple times)",
 		},
 		&cli.BoolFlag{
 			Name:  "delete",
 			Usage: "delete variables not defined in local",
 			Value: false,
 		},
+		&cli.BoolFlag{
+			Name:  "sync-descriptions",
+			Usage: "update descriptions with comments above each variable",
+			Value: false,
+		},
 		&cli.BoolFlag{
 			Name:  "auto-approve",
 			Usage: "Skip approve",
 			Value: false,
 		},
+		&cli.BoolFlag{
+			Name:    "interactive",
+			Aliases: []string{"i"},
+			Usage:   "Approve, skip or edit each change interactively",
+			Value:   false,
+		},
 	}
+
+	return append(flags, filterFlags()...)
 }
 
 func diffFlags() []cli.Flag {
-	return []cli.Flag{
+	flags := []cli.Flag{
 		&cli.StringFlag{
 			Name:  "var-file",
 			Usage: "Input filename to push variables",
@@ -197,21 +364,169 @@ func diffFlags() []cli.Flag {
 			Usage: "include Variable Set variables",
 			Value: false,
 		},
+		&cli.StringSliceFlag{
+			Name:  "variable",
+			Usage: "Compare specified variable (can be specified multiple times)",
+		},
+		&cli.BoolFlag{
+			Name:  "detailed-exitcode",
+			Usage: "exit with 0 if no changes, 1 if error, 2 if differences exist",
+			Value: false,
+		},
+		&cli.GenericFlag{
+			Name:  "format",
+			Usage: "format to display difference (text, unified, json, summary)",
+			Value: &FormatType{
+				Enum:    []string{"text", "unified", "json", "summary"},
+				Default: "text",
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "sync-descriptions",
+			Usage: "compare descriptions with comments above each variable",
+			Value: false,
+		},
+		&cli.GenericFlag{
+			Name:  "color",
+			Usage: "colorize output (auto, always, never)",
+			Value: &FormatType{
+				Enum:    []string{COLOR_AUTO, COLOR_ALWAYS, COLOR_NEVER},
+				Default: COLOR_AUTO,
+			},
+		},
+		&cli.IntFlag{
+			Name:        "context",
+			Usage:       "show changes in hunks with number of unchanged lines around them, all lines if negative",
+			DefaultText: "all lines",
+		},
+		&cli.BoolFlag{
+			Name:  "reveal",
+			Usage: "show values which look secret such as password and token without masking",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "type-changes",
+			Usage: "report changes of value type only such as 3000 and \"3000\"",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  "from",
+			Usage: "Compare from ORG/WORKSPACE or var-file (default: current workspace)",
+		},
+		&cli.StringFlag{
+			Name:  "to",
+			Usage: "Compare to ORG/WORKSPACE or var-file (default: var-file)",
+		},
 	}
+
+	return append(flags, filterFlags()...)
 }
 
-func removeFlags() []cli.Flag {
+func filterFlags() []cli.Flag {
 	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "Select variables whose key matches glob pattern (can be specified multiple times)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "Ignore variables whose key matches glob pattern (can be specified multiple times)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "regex",
+			Usage: "Select variables whose key matches regular expression (can be specified multiple times)",
+		},
+		&cli.GenericFlag{
+			Name:  "category",
+			Usage: "Select variables of category (terraform, env)",
+			Value: &FormatType{
+				Enum: []string{string(tfe.CategoryTerraform), string(tfe.CategoryEnv)},
+			},
+		},
+	}
+}
+
+func removeFlags() []cli.Flag {
+	flags := []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "variable",
+			Usage: "Remove specified variable (can be specified multiple times)",
+		},
+		&cli.BoolFlag{
+			Name:  "all",
+			Usage: "Remove all variables",
+			Value: false,
+		},
 		&cli.StringFlag{
+			Name:  "variable-set",
+			Usage: "Remove variables of specified variable set instead of workspace",
+		},
+		&cli.BoolFlag{
+			Name:  "auto-approve",
+			Usage: "Skip approve",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:    "interactive",
+			Aliases: []string{"i"},
+			Usage:   "Approve, skip or edit each change interactively",
+			Value:   false,
+		},
+	}
+
+	return append(flags, filterFlags()...)
+}
+
+func copyFlags() []cli.Flag {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:     "from",
+			Usage:    "Source workspace specified as ORG/WORKSPACE",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "to",
+			Usage:    "Destination workspace specified as ORG/WORKSPACE",
+			Required: true,
+		},
+		&cli.StringSliceFlag{
 			Name:  "variable",
-			Usage: "Remove specified variable",
+			Usage: "Copy specified variable (can be specified multiple times)",
+		},
+		&cli.GenericFlag{
+			Name:  "conflict",
+			Usage: "how to handle variables already defined in destination (skip, overwrite, fail)",
+			Value: &FormatType{
+				Enum:    []string{CONFLICT_SKIP, CONFLICT_OVERWRITE, CONFLICT_FAIL},
+				Default: CONFLICT_SKIP,
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "print planned changes without copying",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  "var-file",
+			Usage: "Input filename to read values of sensitive terraform Category variables",
+		},
+		&cli.StringFlag{
+			Name:  "env-file",
+			Usage: "Input filename in dotenv format to read values of sensitive env Category variables",
 		},
 		&cli.BoolFlag{
 			Name:  "auto-approve",
 			Usage: "Skip approve",
 			Value: false,
 		},
+		&cli.BoolFlag{
+			Name:  "reveal",
+			Usage: "show values which look secret such as password and token without masking",
+			Value: false,
+		},
 	}
+
+	return append(flags, filterFlags()...)
 }
 
 func versionFormatter(version string, revision string) string {
@@ -254,3 +569,200 @@ func getRevision() string {
 
 	return ""
 }
+
+func exportFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "org",
+			Usage: "Organization to export (default: global --organization)",
+		},
+		&cli.StringFlag{
+			Name:  "out",
+			Usage: "Output directory",
+			Value: "tfcvars-backup",
+		},
+		&cli.BoolFlag{
+			Name:  "include-variable-set",
+			Usage: "Export variable sets in organization",
+			Value: false,
+		},
+	}
+}
+
+func restoreFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "org",
+			Usage: "Organization to restore into (default: organization in manifest)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "workspace",
+			Usage: "Restore only specified workspace (can be specified multiple times)",
+		},
+		&cli.BoolFlag{
+			Name:  "include-variable-set",
+			Usage: "Restore variable sets as well",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "delete",
+			Usage: "Delete variables not in backup",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "print planned changes without restoring",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "auto-approve",
+			Usage: "Skip approve",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "reveal",
+			Usage: "show values which look secret such as password and token without masking",
+			Value: false,
+		},
+	}
+}
+
+func historyFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "Number of latest changes to show (0 shows all)",
+			Value: 20,
+		},
+		&cli.GenericFlag{
+			Name:  "format",
+			Usage: "output format (text, json)",
+			Value: &FormatType{
+				Enum:    []string{"text", "json"},
+				Default: "text",
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "reveal",
+			Usage: "show values which look secret such as password and token without masking",
+			Value: false,
+		},
+	}
+}
+
+func lintFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.GenericFlag{
+			Name:  "format",
+			Usage: "output format (text, json, sarif)",
+			Value: &FormatType{
+				Enum:    []string{"text", "json", "sarif"},
+				Default: "text",
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "remote",
+			Usage: "report values of variables sensitive in Terraform Cloud workspace",
+			Value: false,
+		},
+	}
+}
+
+func guardFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "include-variable-set",
+			Usage: "check variables sensitive in variable sets applied to workspace",
+			Value: false,
+		},
+		&cli.GenericFlag{
+			Name:  "format",
+			Usage: "output format (text, json, sarif)",
+			Value: &FormatType{
+				Enum:    []string{"text", "json", "sarif"},
+				Default: "text",
+			},
+		},
+	}
+}
+
+func undoFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:  "id",
+			Usage: "ID of change to revert (default: latest change)",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "print planned changes without reverting",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "auto-approve",
+			Usage: "Skip approve",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "reveal",
+			Usage: "show values which look secret such as password and token without masking",
+			Value: false,
+		},
+	}
+}
+
+func watchFlags() []cli.Flag {
+	flags := []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "target",
+			Usage: "Workspace and var-file to compare, specified as ORG/WORKSPACE=FILE (can be specified multiple times)",
+		},
+		&cli.StringFlag{
+			Name:  "var-file",
+			Usage: "Default var-file compared with workspaces",
+			Value: "terraform.tfvars",
+		},
+		&cli.DurationFlag{
+			Name:  "interval",
+			Usage: "Interval between checks",
+			Value: 5 * time.Minute,
+		},
+		&cli.BoolFlag{
+			Name:  "once",
+			Usage: "Check drift once and exit",
+			Value: false,
+		},
+		&cli.GenericFlag{
+			Name:  "format",
+			Usage: "event format (text, json)",
+			Value: &FormatType{
+				Enum:    []string{"text", "json"},
+				Default: "text",
+			},
+		},
+		&cli.StringFlag{
+			Name:  "webhook",
+			Usage: "URL to POST drift events as JSON",
+		},
+		&cli.StringFlag{
+			Name:  "state-file",
+			Usage: "File to save drift already reported",
+			Value: ".tfcvars-watch-state.json",
+		},
+		&cli.BoolFlag{
+			Name:  "include-variable-set",
+			Usage: "include variable set",
+			Value: false,
+		},
+		&cli.StringSliceFlag{
+			Name:  "variable",
+			Usage: "Watch only specified variable (can be specified multiple times)",
+		},
+		&cli.BoolFlag{
+			Name:  "sync-descriptions",
+			Usage: "Report description changes as drift",
+			Value: false,
+		},
+	}
+
+	return append(flags, filterFlags()...)
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"math"
+	"path"
+	"regexp"
+	"strings"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// sensitiveMask is displayed instead of values of sensitive variables
+const sensitiveMask = "(sensitive)"
+
+const (
+	// redactedMask is displayed instead of values which look secret but are not marked as sensitive
+	redactedMask = "(redacted)"
+	// redactedChangedMask is displayed instead of secret-looking value which differs from the other side
+	redactedChangedMask = "(redacted, changed)"
+)
+
+// secretKeyPatterns is glob patterns of keys whose values are treated as secret, matched case insensitively
+var secretKeyPatterns = []string{
+	"*password*",
+	"*passwd*",
+	"*token*",
+	"*secret*",
+	"*credential*",
+	"*private_key*",
+	"*api_key*",
+	"*apikey*",
+}
+
+var (
+	tokenValue = regexp.MustCompile(`^[A-Za-z0-9+/=_\-]+$`)
+	hexValue   = regexp.MustCompile(`^[0-9A-Fa-f]+$`)
+	// resourceIdValue is ID of cloud resources such as ami-0123456789abcdef0 and subnet-0123abcd
+	resourceIdValue = regexp.MustCompile(`^[a-z]+-[0-9a-f]{8,17}$`)
+	// commitHashValue is SHA-1 hash of git commit
+	commitHashValue = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// sensitiveKeys return set of keys marked as sensitive in any of variable lists
+func sensitiveKeys(varsList ...[]*tfe.Variable) map[string]bool {
+	keys := map[string]bool{}
+
+	for _, vars := range varsList {
+		for _, v := range vars {
+			if v.Sensitive {
+				keys[v.Key] = true
+			}
+		}
+	}
+
+	return keys
+}
+
+// redactVariables return copy of variables whose values are masked if the key is sensitive
+func redactVariables(vars []*tfe.Variable, sensitive map[string]bool) []*tfe.Variable {
+	masks := map[string]string{}
+	for _, v := range vars {
+		if v.Sensitive || sensitive[v.Key] {
+			masks[v.Key] = sensitiveMask
+		}
+	}
+
+	return maskVariables(vars, masks)
+}
+
+// maskVariables return copy of variables whose values are replaced with masks by key
+func maskVariables(vars []*tfe.Variable, masks map[string]string) []*tfe.Variable {
+	masked := make([]*tfe.Variable, 0, len(vars))
+
+	for _, v := range vars {
+		mask, ok := masks[v.Key]
+		if !ok {
+			masked = append(masked, v)
+			continue
+		}
+
+		m := *v
+		m.Value = mask
+		m.HCL = false
+		masked = append(masked, &m)
+	}
+
+	return masked
+}
+
+// looksSecret return true if key matches secret key patterns or value looks like random token
+func looksSecret(v *tfe.Variable) bool {
+	key := strings.ToLower(v.Key)
+	for _, pattern := range secretKeyPatterns {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+
+	return !v.HCL && isHighEntropy(v.Value)
+}
+
+// isHighEntropy return true if value looks like generated token or key
+// hex string requires 32 characters, and other string requires 20 characters including letters and digits
+// IDs of cloud resources and git commit hashes are not secret even though they look random
+func isHighEntropy(value string) bool {
+	if resourceIdValue.MatchString(value) || commitHashValue.MatchString(value) {
+		return false
+	}
+	if hexValue.MatchString(value) {
+		return len(value) >= 32 && shannonEntropy(value) >= 3.0
+	}
+	if len(value) < 20 || !tokenValue.MatchString(value) {
+		return false
+	}
+	if !strings.ContainsAny(value, "0123456789") || strings.IndexFunc(value, isLetter) < 0 {
+		return false
+	}
+
+	return shannonEntropy(value) >= 4.0
+}
+
+func isLetter(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
+
+// shannonEntropy return bits per character of value
+func shannonEntropy(value string) float64 {
+	counts := map[rune]int{}
+	for _, r := range value {
+		counts[r]++
+	}
+
+	entropy := 0.0
+	length := float64(len([]rune(value)))
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
+
+// redactSecrets return copy of variables whose values look secret masked
+// sensitive variables are kept as is since their values are not retrieved
+func redactSecrets(vars []*tfe.Variable) []*tfe.Variable {
+	masks := map[string]string{}
+	for _, v := range vars {
+		if !v.Sensitive && looksSecret(v) {
+			masks[v.Key] = redactedMask
+		}
+	}
+
+	return maskVariables(vars, masks)
+}
+
+// redactValue return masked value if sensitive
+func redactValue(value string, sensitive bool) string {
+	if sensitive {
+		return sensitiveMask
+	}
+
+	return value
+}
+
+// redact return copy of tfvars file whose sensitive attribute values are masked
+func (vf *Tfvars) redact(sensitive map[string]bool) *Tfvars {
+	masks := map[string]string{}
+	for key := range sensitive {
+		masks[key] = sensitiveMask
+	}
+
+	return vf.mask(masks)
+}
+
+// mask return copy of tfvars file whose attribute values are replaced with masks by key
+func (vf *Tfvars) mask(masks map[string]string) *Tfvars {
+	if len(masks) == 0 {
+		return vf
+	}
+
+	masked := &Tfvars{
+		filename: vf.filename,
+		vardata:  vf.vardata,
+		vars:     maskVariables(vf.vars, masks),
+	}
+
+	f, diags := hclwrite.ParseConfig(vf.vardata, vf.filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return masked
+	}
+	for key := range f.Body().Attributes() {
+		if mask, ok := masks[key]; ok {
+			f.Body().SetAttributeValue(key, cty.StringVal(mask))
+		}
+	}
+	masked.vardata = f.Bytes()
+
+	return masked
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tfe "github.com/hashicorp/go-tfe"
+)
+
+func TestSensitiveKeys(t *testing.T) {
+	remote := []*tfe.Variable{
+		{Key: "environment", Value: "test"},
+		{Key: "db_password", Sensitive: true},
+	}
+	local := []*tfe.Variable{
+		{Key: "api_token", Value: "token", Sensitive: true},
+		{Key: "db_password", Value: "supersecret"},
+	}
+	expect := map[string]bool{
+		"db_password": true,
+		"api_token":   true,
+	}
+
+	actual := sensitiveKeys(remote, local)
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect '%v', got '%v'", expect, actual)
+	}
+}
+
+func TestRedactVariables(t *testing.T) {
+	vars := []*tfe.Variable{
+		{Key: "environment", Value: "test"},
+		{Key: "db_password", Value: "supersecret"},
+		{Key: "hosts", Value: `["secret-host"]`, HCL: true, Sensitive: true},
+	}
+	expect := []*tfe.Variable{
+		{Key: "environment", Value: "test"},
+		{Key: "db_password", Value: "(sensitive)"},
+		{Key: "hosts", Value: "(sensitive)", HCL: false, Sensitive: true},
+	}
+
+	actual := redactVariables(vars, map[string]bool{"db_password": true})
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect '%v', got '%v'", expect, actual)
+	}
+	if vars[1].Value != "supersecret" {
+		t.Errorf("expect original variable not modified, got '%s'", vars[1].Value)
+	}
+}
+
+func TestTfvars_Redact(t *testing.T) {
+	vf, err := NewTfvarsFile("testdata/sensitive.tfvars")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := vf.redact(map[string]bool{"db_password": true})
+
+	if strings.Contains(string(actual.vardata), "supersecret") {
+		t.Errorf("expect secret to be masked, got '%s'", actual.vardata)
+	}
+	if !strings.Contains(actual.BuildHCLFileString(), `db_password = "(sensitive)"`) {
+		t.Errorf("expect masked attribute, got '%s'", actual.vardata)
+	}
+	if !strings.Contains(string(vf.vardata), "supersecret") {
+		t.Errorf("expect original file not modified, got '%s'", vf.vardata)
+	}
+}
+
+func TestPushOptionString(t *testing.T) {
+	opt := &PushOption{varFile: "terraform.tfvars", variables: []*tfe.Variable{{Key: "db_password", Value: "supersecret"}}}
+
+	actual := opt.String()
+	if strings.Contains(actual, "supersecret") {
+		t.Errorf("expect value to be masked, got '%s'", actual)
+	}
+	if !strings.Contains(actual, "variables:[db_password]") {
+		t.Errorf("expect key to be shown, got '%s'", actual)
+	}
+}
+
+func TestLooksSecret(t *testing.T) {
+	cases := []struct {
+		name     string
+		variable *tfe.Variable
+		expect   bool
+	}{
+		{
+			name:     "plain value",
+			variable: &tfe.Variable{Key: "environment", Value: "production"},
+			expect:   false,
+		},
+		{
+			name:     "secret key pattern",
+			variable: &tfe.Variable{Key: "DB_PASSWORD", Value: "changeme"},
+			expect:   true,
+		},
+		{
+			name:     "token key pattern",
+			variable: &tfe.Variable{Key: "github_token", Value: ""},
+			expect:   true,
+		},
+		{
+			name:     "random token value",
+			variable: &tfe.Variable{Key: "deploy_key", Value: "AKIAx7Qp2Lm9Zr4Tw8Yb3Nc6"},
+			expect:   true,
+		},
+		{
+			name:     "hex digest value",
+			variable: &tfe.Variable{Key: "checksum", Value: "9f86d081884c7d659a2feaa0c55ad015"},
+			expect:   true,
+		},
+		{
+			name:     "ami id",
+			variable: &tfe.Variable{Key: "ami", Value: "ami-0abcdef1234567890"},
+			expect:   false,
+		},
+		{
+			name:     "subnet id",
+			variable: &tfe.Variable{Key: "subnet_id", Value: "subnet-0bb1c79de3a1b2c3d"},
+			expect:   false,
+		},
+		{
+			name:     "vpc id",
+			variable: &tfe.Variable{Key: "vpc_id", Value: "vpc-1a2b3c4d5e6f7a8b9"},
+			expect:   false,
+		},
+		{
+			name:     "security group id",
+			variable: &tfe.Variable{Key: "security_group", Value: "sg-903004f88a1b2c3d4"},
+			expect:   false,
+		},
+		{
+			name:     "git commit hash",
+			variable: &tfe.Variable{Key: "app_version", Value: "e5f4d0d8a6b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5"},
+			expect:   false,
+		},
+		{
+			name:     "long identifier without digits",
+			variable: &tfe.Variable{Key: "name", Value: "my_application_name_production"},
+			expect:   false,
+		},
+		{
+			name:     "url value",
+			variable: &tfe.Variable{Key: "endpoint", Value: "https://example.com/api/v1/resources"},
+			expect:   false,
+		},
+		{
+			name:     "HCL value is not checked for entropy",
+			variable: &tfe.Variable{Key: "ids", Value: `["AKIAx7Qp2Lm9Zr4Tw8Yb3Nc6"]`, HCL: true},
+			expect:   false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := looksSecret(tt.variable)
+			if tt.expect != actual {
+				t.Errorf("expect '%v', got '%v'", tt.expect, actual)
+			}
+		})
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	vars := []*tfe.Variable{
+		{Key: "environment", Value: "test"},
+		{Key: "api_token", Value: "token"},
+		{Key: "db_password", Sensitive: true},
+	}
+	expect := []*tfe.Variable{
+		{Key: "environment", Value: "test"},
+		{Key: "api_token", Value: "(redacted)"},
+		{Key: "db_password", Sensitive: true},
+	}
+
+	actual := redactSecrets(vars)
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect '%v', got '%v'", expect, actual)
+	}
+	if vars[1].Value != "token" {
+		t.Errorf("expect original variable not modified, got '%s'", vars[1].Value)
+	}
+}
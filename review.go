@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+var errNotTerminal = errors.New("stdin is not a terminal: use --auto-approve to apply changes without confirmation")
+
+var errNoInput = errors.New("no input for confirmation: use --auto-approve to apply changes without confirmation")
+
+// requireTerminal return error if confirmation is read from non-interactive stdin
+func requireTerminal(in io.Reader) error {
+	f, ok := in.(*os.File)
+	if !ok {
+		// readers other than file (e.g. input for testing) are treated as interactive
+		return nil
+	}
+
+	if !isatty.IsTerminal(f.Fd()) && !isatty.IsCygwinTerminal(f.Fd()) {
+		return errNotTerminal
+	}
+
+	return nil
+}
+
+// interactiveApprove walk each planned operation, print summary and confirm
+// return operations to be applied. empty list is returned if nothing should be applied.
+func interactiveApprove(in io.Reader, out io.Writer, variables []*PushVariable) ([]*PushVariable, error) {
+	if len(variables) == 0 {
+		fmt.Fprintln(out, "No changes.")
+		return variables, nil
+	}
+	if err := requireTerminal(in); err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(in)
+	accepted, quit, err := reviewPushVariables(r, out, variables)
+	if err != nil {
+		return nil, err
+	}
+	if quit {
+		fmt.Fprintln(out, "cancelled")
+		return []*PushVariable{}, nil
+	}
+
+	fmt.Fprintf(out, "\n%s\n", summarizePushVariables(accepted, len(variables)-len(accepted)))
+	if len(accepted) == 0 {
+		return accepted, nil
+	}
+	fmt.Fprint(out, "Are you sure you want to change variables in Terraform Cloud? [y/n]: ")
+	res, err := confirm(r)
+	if err != nil {
+		return nil, err
+	}
+	if !res {
+		return []*PushVariable{}, nil
+	}
+
+	return accepted, nil
+}
+
+// reviewPushVariables ask for each planned operation whether to accept, skip or edit it
+// and return accepted operations. quit is true if user cancel entire operations.
+func reviewPushVariables(in *bufio.Reader, out io.Writer, variables []*PushVariable) (accepted []*PushVariable, quit bool, err error) {
+	accepted = []*PushVariable{}
+
+	for idx, variable := range variables {
+	promptLoop:
+		for {
+			// values are shown as is since they may be edited here
+			fmt.Fprintf(out, "\n[%d/%d] %s\n", idx+1, len(variables), describePushVariable(variable, true))
+			fmt.Fprint(out, "Apply this change? [a]ccept, [s]kip, [e]dit, [q]uit: ")
+
+			input, err := readLine(in)
+			if err != nil {
+				return nil, false, err
+			}
+
+			switch strings.ToLower(input) {
+			case "a", "accept", "y", "yes":
+				accepted = append(accepted, variable)
+				break promptLoop
+			case "s", "skip", "n", "no":
+				break promptLoop
+			case "e", "edit":
+				if variable.operation == PUSH_OPERATION_DELETE {
+					fmt.Fprintln(out, "delete operation cannot be edited")
+					continue
+				}
+				fmt.Fprintf(out, "New value for %s: ", variable.key())
+				value, err := readLine(in)
+				if err != nil {
+					return nil, false, err
+				}
+				variable.setValue(value)
+			case "q", "quit":
+				return nil, true, nil
+			default:
+				fmt.Fprintf(out, "unknown answer '%s'\n", input)
+			}
+		}
+	}
+
+	return accepted, false, nil
+}
+
+// describePushVariable return one line description of planned operation
+// values which look secret are masked unless reveal
+func describePushVariable(variable *PushVariable, reveal bool) string {
+	switch variable.operation {
+	case PUSH_OPERATION_CREATE:
+		value := *variable.createOption.Value
+		if variable.sensitive() {
+			value = sensitiveMask
+		} else if !reveal && looksSecret(variable.result()) {
+			value = redactedMask
+		}
+		return fmt.Sprintf("create %s = %q", variable.key(), value)
+	case PUSH_OPERATION_UPDATE:
+		previous := ""
+		if variable.previous != nil {
+			previous = variable.previous.Value
+		}
+		value := variable.result().Value
+		switch {
+		case variable.sensitive():
+			previous, value = sensitiveMask, sensitiveMask
+		case !reveal && variable.previous != nil && (looksSecret(variable.previous) || looksSecret(variable.result())):
+			changed := previous != value
+			previous, value = redactedMask, redactedMask
+			if changed {
+				value = redactedChangedMask
+			}
+		}
+		description := fmt.Sprintf("update %s: %q -> %q", variable.key(), previous, value)
+		for _, change := range variable.metadataChanges() {
+			description += "\n" + formatMetadataChange(variable.key(), change)
+		}
+		return description
+	case PUSH_OPERATION_DELETE:
+		return fmt.Sprintf("delete %s", variable.key())
+	}
+
+	return fmt.Sprintf("unknown operation '%s' for %s", variable.operation, variable.key())
+}
+
+// summarizePushVariables return count of each operation
+func summarizePushVariables(variables []*PushVariable, skipped int) string {
+	countCreate := 0
+	countUpdate := 0
+	countDelete := 0
+	for _, variable := range variables {
+		switch variable.operation {
+		case PUSH_OPERATION_CREATE:
+			countCreate++
+		case PUSH_OPERATION_UPDATE:
+			countUpdate++
+		case PUSH_OPERATION_DELETE:
+			countDelete++
+		}
+	}
+
+	return fmt.Sprintf("Plan: %d to create, %d to update, %d to delete, %d skipped.", countCreate, countUpdate, countDelete, skipped)
+}
+
+func readLine(in *bufio.Reader) (string, error) {
+	input, err := in.ReadString('\n')
+	if errors.Is(err, io.EOF) {
+		return "", errNoInput
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(input), nil
+}
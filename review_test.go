@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tfe "github.com/hashicorp/go-tfe"
+)
+
+func TestRequireTerminal(t *testing.T) {
+	fp, err := os.Create(filepath.Join(t.TempDir(), "stdin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fp.Close()
+
+	cases := []struct {
+		name    string
+		in      io.Reader
+		wantErr bool
+	}{
+		{
+			name:    "accept reader for testing",
+			in:      strings.NewReader("yes\n"),
+			wantErr: false,
+		},
+		{
+			name:    "reject regular file",
+			in:      fp,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := requireTerminal(tt.in)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expect error, got no error")
+				} else if !strings.Contains(err.Error(), "--auto-approve") {
+					t.Errorf("expect error suggests --auto-approve, got '%v'", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+		})
+	}
+}
+
+func TestReviewPushVariables(t *testing.T) {
+	newVariables := func() []*PushVariable {
+		return []*PushVariable{
+			{
+				operation: PUSH_OPERATION_CREATE,
+				createOption: tfe.VariableCreateOptions{
+					Key:   tfe.String("environment"),
+					Value: tfe.String("test"),
+				},
+			},
+			{
+				operation: PUSH_OPERATION_UPDATE,
+				id:        "var-port",
+				previous:  &tfe.Variable{ID: "var-port", Key: "port", Value: "3000"},
+				updateOption: tfe.VariableUpdateOptions{
+					Key:   tfe.String("port"),
+					Value: tfe.String("8080"),
+				},
+			},
+			{
+				operation: PUSH_OPERATION_DELETE,
+				id:        "var-region",
+				previous:  &tfe.Variable{ID: "var-region", Key: "region", Value: "ap-northeast-1"},
+			},
+		}
+	}
+
+	cases := []struct {
+		name         string
+		input        string
+		expectKeys   []string
+		expectValues []string
+		expectQuit   bool
+		wantErr      bool
+	}{
+		{
+			name:         "accept all changes",
+			input:        "a\ny\naccept\n",
+			expectKeys:   []string{"environment", "port", "region"},
+			expectValues: []string{"test", "8080", ""},
+		},
+		{
+			name:         "skip some changes",
+			input:        "s\nyes\nn\n",
+			expectKeys:   []string{"port"},
+			expectValues: []string{"8080"},
+		},
+		{
+			name:         "edit value before accept",
+			input:        "e\nproduction\na\nskip\ns\n",
+			expectKeys:   []string{"environment"},
+			expectValues: []string{"production"},
+		},
+		{
+			name:         "ignore edit for delete and unknown answer",
+			input:        "s\ns\ne\nunknown\na\n",
+			expectKeys:   []string{"region"},
+			expectValues: []string{""},
+		},
+		{
+			name:       "quit review",
+			input:      "a\nq\n",
+			expectQuit: true,
+		},
+		{
+			name:    "return error if input closed",
+			input:   "a\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			in := bufio.NewReader(strings.NewReader(tt.input))
+
+			accepted, quit, err := reviewPushVariables(in, &out, newVariables())
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expect error, got no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+			if quit != tt.expectQuit {
+				t.Errorf("expect quit '%t', got '%t'", tt.expectQuit, quit)
+			}
+			if quit {
+				return
+			}
+			if len(accepted) != len(tt.expectKeys) {
+				t.Fatalf("expect %d accepted changes, got %d", len(tt.expectKeys), len(accepted))
+			}
+			for i, v := range accepted {
+				if v.key() != tt.expectKeys[i] {
+					t.Errorf("expect key '%s', got '%s'", tt.expectKeys[i], v.key())
+				}
+				value := ""
+				switch v.operation {
+				case PUSH_OPERATION_CREATE:
+					value = *v.createOption.Value
+				case PUSH_OPERATION_UPDATE:
+					value = *v.updateOption.Value
+				}
+				if value != tt.expectValues[i] {
+					t.Errorf("expect value '%s', got '%s'", tt.expectValues[i], value)
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizePushVariables(t *testing.T) {
+	variables := []*PushVariable{
+		{operation: PUSH_OPERATION_CREATE},
+		{operation: PUSH_OPERATION_UPDATE},
+		{operation: PUSH_OPERATION_UPDATE},
+		{operation: PUSH_OPERATION_DELETE},
+	}
+	expect := "Plan: 1 to create, 2 to update, 1 to delete, 3 skipped."
+
+	actual := summarizePushVariables(variables, 3)
+	if actual != expect {
+		t.Errorf("expect '%s', got '%s'", expect, actual)
+	}
+}
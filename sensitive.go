@@ -0,0 +1,138 @@
+package main
+
+import (
+	"path/filepath"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rs/zerolog/log"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	SENSITIVE_COMMENT     = "comment"
+	SENSITIVE_OMIT        = "omit"
+	SENSITIVE_KEEP_LOCAL  = "keep-local"
+	SENSITIVE_PLACEHOLDER = "placeholder"
+)
+
+// SensitiveOption describe how sensitive variables are written into tfvars file
+type SensitiveOption struct {
+	strategy     string
+	localFile    []byte
+	declarations map[string]*VariableDeclaration
+}
+
+// NewSensitiveOption build SensitiveOption for tfvars file written to varFile
+// localFile is existing contents of varFile used by keep-local strategy
+func NewSensitiveOption(strategy string, varFile string, localFile []byte) *SensitiveOption {
+	opt := &SensitiveOption{
+		strategy:  strategy,
+		localFile: localFile,
+	}
+
+	if strategy == SENSITIVE_PLACEHOLDER {
+		declarations, err := loadVariableDeclarations(filepath.Dir(varFile))
+		if err != nil {
+			log.Warn().Err(err).Msg("failed to load variable declarations")
+		}
+		opt.declarations = declarations
+	}
+
+	return opt
+}
+
+// getStrategy return strategy, which defaults to comment
+func (opt *SensitiveOption) getStrategy() string {
+	if opt == nil || opt.strategy == "" {
+		return SENSITIVE_COMMENT
+	}
+
+	return opt.strategy
+}
+
+// writeSensitive write sensitive variable into body according to strategy
+// attribute already in body, which is kept from local file by merge, is replaced unless keep-local strategy
+func (opt *SensitiveOption) writeSensitive(body *hclwrite.Body, v *tfe.Variable) {
+	switch opt.getStrategy() {
+	case SENSITIVE_OMIT:
+		body.RemoveAttribute(v.Key)
+	case SENSITIVE_KEEP_LOCAL:
+		if body.GetAttribute(v.Key) != nil {
+			return
+		}
+		if tokens := opt.localAttributeTokens(v.Key); tokens != nil {
+			body.SetAttributeRaw(v.Key, tokens)
+			return
+		}
+		// nothing to keep, notify that the variable exists
+		body.AppendUnstructuredTokens(generateComment(v.Key))
+	case SENSITIVE_PLACEHOLDER:
+		body.SetAttributeValue(v.Key, opt.placeholder(v))
+	default:
+		body.RemoveAttribute(v.Key)
+		body.AppendUnstructuredTokens(generateComment(v.Key))
+	}
+}
+
+// localAttributeTokens return expression tokens of key defined in local file
+func (opt *SensitiveOption) localAttributeTokens(key string) hclwrite.Tokens {
+	if len(opt.localFile) == 0 {
+		return nil
+	}
+
+	f, diags := hclwrite.ParseConfig(opt.localFile, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		log.Warn().Msgf("failed to parse local varfile: %s", diags.Error())
+		return nil
+	}
+	attr := f.Body().GetAttribute(key)
+	if attr == nil {
+		return nil
+	}
+
+	return attr.Expr().BuildTokens(nil)
+}
+
+// placeholder return dummy value typed with variable declaration
+func (opt *SensitiveOption) placeholder(v *tfe.Variable) cty.Value {
+	if declaration, ok := opt.declarations[v.Key]; ok && declaration.Type != cty.DynamicPseudoType {
+		return placeholderValue(declaration.Type)
+	}
+
+	if v.HCL {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+	return cty.StringVal("")
+}
+
+// placeholderValue return zero value of ty
+func placeholderValue(ty cty.Type) cty.Value {
+	switch {
+	case ty == cty.String:
+		return cty.StringVal("")
+	case ty == cty.Number:
+		return cty.Zero
+	case ty == cty.Bool:
+		return cty.False
+	case ty.IsListType(), ty.IsSetType():
+		return cty.EmptyTupleVal
+	case ty.IsMapType():
+		return cty.EmptyObjectVal
+	case ty.IsTupleType():
+		elems := []cty.Value{}
+		for _, elemType := range ty.TupleElementTypes() {
+			elems = append(elems, placeholderValue(elemType))
+		}
+		return cty.TupleVal(elems)
+	case ty.IsObjectType():
+		attrs := map[string]cty.Value{}
+		for name, attrType := range ty.AttributeTypes() {
+			attrs[name] = placeholderValue(attrType)
+		}
+		return cty.ObjectVal(attrs)
+	}
+
+	return cty.NullVal(cty.DynamicPseudoType)
+}
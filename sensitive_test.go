@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+func TestSensitiveOption_WriteSensitive(t *testing.T) {
+	opt := NewSensitiveOption(SENSITIVE_PLACEHOLDER, "testdata/config/terraform.tfvars", nil)
+
+	cases := []struct {
+		name     string
+		variable *tfe.Variable
+		expect   string
+	}{
+		{
+			name:     "string placeholder",
+			variable: &tfe.Variable{Key: "db_password", Sensitive: true},
+			expect:   "db_password = \"\"\n",
+		},
+		{
+			name:     "number placeholder",
+			variable: &tfe.Variable{Key: "db_port", HCL: true, Sensitive: true},
+			expect:   "db_port = 0\n",
+		},
+		{
+			name:     "object placeholder",
+			variable: &tfe.Variable{Key: "db_options", HCL: true, Sensitive: true},
+			expect:   "db_options = {\n  ssl     = false\n  timeout = 0\n}\n",
+		},
+		{
+			name:     "list placeholder",
+			variable: &tfe.Variable{Key: "db_hosts", HCL: true, Sensitive: true},
+			expect:   "db_hosts = []\n",
+		},
+		{
+			name:     "untyped HCL placeholder",
+			variable: &tfe.Variable{Key: "untyped", HCL: true, Sensitive: true},
+			expect:   "untyped = null\n",
+		},
+		{
+			name:     "undeclared string placeholder",
+			variable: &tfe.Variable{Key: "undeclared", Sensitive: true},
+			expect:   "undeclared = \"\"\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			f := hclwrite.NewEmptyFile()
+			opt.writeSensitive(f.Body(), tt.variable)
+
+			if actual := string(f.Bytes()); actual != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, actual)
+			}
+		})
+	}
+}
+
+func TestSensitiveOption_WriteSensitiveMerge(t *testing.T) {
+	cases := []struct {
+		name     string
+		strategy string
+		expect   string
+	}{
+		{
+			name:     "comment replaces existing value",
+			strategy: SENSITIVE_COMMENT,
+			expect:   "region = \"ap-northeast-1\"\n// db_password = \"***\"\n",
+		},
+		{
+			name:     "omit removes existing value",
+			strategy: SENSITIVE_OMIT,
+			expect:   "region = \"ap-northeast-1\"\n",
+		},
+		{
+			name:     "keep-local keeps existing value",
+			strategy: SENSITIVE_KEEP_LOCAL,
+			expect:   "region      = \"ap-northeast-1\"\ndb_password = \"supersecret\"\n",
+		},
+		{
+			name:     "placeholder replaces existing value",
+			strategy: SENSITIVE_PLACEHOLDER,
+			expect:   "region      = \"ap-northeast-1\"\ndb_password = \"\"\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			src := []byte("region      = \"ap-northeast-1\"\ndb_password = \"supersecret\"\n")
+			f, diags := hclwrite.ParseConfig(src, "", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+			opt := NewSensitiveOption(tt.strategy, "terraform.tfvars", src)
+			opt.writeSensitive(f.Body(), &tfe.Variable{Key: "db_password", Sensitive: true})
+
+			if actual := string(f.Bytes()); actual != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/olekukonko/tablewriter"
+	"github.com/zclconf/go-cty/cty"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	SOURCE_WORKSPACE = "workspace"
+	SOURCE_VARSET    = "varset"
+	SOURCE_LOCAL     = "local"
+)
+
+// VariableOutput is variable with full metadata for structured output formats
+type VariableOutput struct {
+	ID          string `json:"id" yaml:"id"`
+	Key         string `json:"key" yaml:"key"`
+	Value       string `json:"value" yaml:"value"`
+	Description string `json:"description" yaml:"description"`
+	Category    string `json:"category" yaml:"category"`
+	HCL         bool   `json:"hcl" yaml:"hcl"`
+	Sensitive   bool   `json:"sensitive" yaml:"sensitive"`
+	Source      string `json:"source" yaml:"source"`
+	VariableSet string `json:"variable_set,omitempty" yaml:"variable_set,omitempty"`
+}
+
+// VariableSources record variable set name of each variable, other variables are defined in workspace or local file
+type VariableSources struct {
+	local        bool
+	variableSets map[*tfe.Variable]string
+}
+
+func (s *VariableSources) source(v *tfe.Variable) (string, string) {
+	if s == nil {
+		return SOURCE_WORKSPACE, ""
+	}
+	if s.local {
+		return SOURCE_LOCAL, ""
+	}
+	if name, ok := s.variableSets[v]; ok {
+		return SOURCE_VARSET, name
+	}
+
+	return SOURCE_WORKSPACE, ""
+}
+
+// selectSource return variables defined in source, all variables if source is empty
+func selectSource(variables []*tfe.Variable, sources *VariableSources, source string) []*tfe.Variable {
+	if source == "" {
+		return variables
+	}
+
+	selected := []*tfe.Variable{}
+	for _, v := range variables {
+		if s, _ := sources.source(v); s == source {
+			selected = append(selected, v)
+		}
+	}
+
+	return selected
+}
+
+// sortVariables sort variables by key, category or source, and then by key
+// order is kept if sortBy is empty
+func sortVariables(variables []*tfe.Variable, sources *VariableSources, sortBy string) {
+	if sortBy == "" {
+		return
+	}
+
+	sortKey := func(v *tfe.Variable) string {
+		switch sortBy {
+		case "category":
+			return string(variableCategory(v))
+		case "source":
+			source, variableSet := sources.source(v)
+			return source + "/" + variableSet
+		}
+		return ""
+	}
+
+	sort.SliceStable(variables, func(i, j int) bool {
+		ki, kj := sortKey(variables[i]), sortKey(variables[j])
+		if ki != kj {
+			return ki < kj
+		}
+		return variables[i].Key < variables[j].Key
+	})
+}
+
+func newVariableOutputs(variables []*tfe.Variable, sources *VariableSources) []*VariableOutput {
+	outputs := make([]*VariableOutput, 0, len(variables))
+
+	for _, v := range variables {
+		source, variableSet := sources.source(v)
+		outputs = append(outputs, &VariableOutput{
+			ID:          v.ID,
+			Key:         v.Key,
+			Value:       v.Value,
+			Description: v.Description,
+			Category:    string(variableCategory(v)),
+			HCL:         v.HCL,
+			Sensitive:   v.Sensitive,
+			Source:      source,
+			VariableSet: variableSet,
+		})
+	}
+
+	return outputs
+}
+
+// writeVariablesJSON print variables as JSON array
+func writeVariablesJSON(w io.Writer, outputs []*VariableOutput) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(outputs)
+}
+
+// writeVariablesYAML print variables as YAML sequence
+func writeVariablesYAML(w io.Writer, outputs []*VariableOutput) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+
+	return encoder.Encode(outputs)
+}
+
+// writeVariablesCSV print variables as CSV with header line
+func writeVariablesCSV(w io.Writer, outputs []*VariableOutput) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{"id", "key", "value", "description", "category", "hcl", "sensitive", "source", "variable_set"})
+	if err != nil {
+		return err
+	}
+	for _, v := range outputs {
+		err = writer.Write([]string{v.ID, v.Key, v.Value, v.Description, v.Category, strconv.FormatBool(v.HCL), strconv.FormatBool(v.Sensitive), v.Source, v.VariableSet})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// tableColumns is columns available in table format with its header
+var tableColumns = map[string]string{
+	"id":           "ID",
+	"key":          "Key",
+	"value":        "Value",
+	"sensitive":    "Sensitive",
+	"description":  "Description",
+	"category":     "Category",
+	"hcl":          "HCL",
+	"source":       "Source",
+	"variable_set": "Variable Set",
+}
+
+var defaultTableColumns = []string{"key", "value", "sensitive", "description"}
+
+// validateColumns check columns are available in table format
+func validateColumns(columns []string) error {
+	for _, column := range columns {
+		if _, ok := tableColumns[column]; !ok {
+			return fmt.Errorf("invalid column '%s', allowed columns are id, key, value, sensitive, description, category, hcl, source, variable_set", column)
+		}
+	}
+
+	return nil
+}
+
+// writeVariablesTable print variables as table of specified columns
+func writeVariablesTable(w io.Writer, outputs []*VariableOutput, columns []string) error {
+	if len(columns) == 0 {
+		columns = defaultTableColumns
+	}
+	if err := validateColumns(columns); err != nil {
+		return err
+	}
+
+	header := []string{}
+	for _, column := range columns {
+		header = append(header, tableColumns[column])
+	}
+	var data [][]string
+	for _, v := range outputs {
+		row := []string{}
+		for _, column := range columns {
+			row = append(row, v.column(column))
+		}
+		data = append(data, row)
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.AppendBulk(data)
+	table.Render()
+
+	return nil
+}
+
+func (v *VariableOutput) column(name string) string {
+	switch name {
+	case "id":
+		return v.ID
+	case "key":
+		return v.Key
+	case "value":
+		return v.Value
+	case "sensitive":
+		return strconv.FormatBool(v.Sensitive)
+	case "description":
+		return v.Description
+	case "category":
+		return v.Category
+	case "hcl":
+		return strconv.FormatBool(v.HCL)
+	case "source":
+		return v.Source
+	case "variable_set":
+		return v.VariableSet
+	}
+
+	return ""
+}
+
+var shellIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// writeVariablesExport print shell export statements
+// terraform variables are exported as TF_VAR_key and env variables as is
+func writeVariablesExport(w io.Writer, variables []*tfe.Variable) {
+	for _, v := range variables {
+		name := v.Key
+		if variableCategory(v) == tfe.CategoryTerraform {
+			name = "TF_VAR_" + v.Key
+		}
+
+		switch {
+		case !shellIdentifier.MatchString(name):
+			fmt.Fprintf(w, "# %s cannot be exported: invalid name\n", name)
+		case v.Sensitive:
+			fmt.Fprintf(w, "# export %s=***\n", name)
+		default:
+			fmt.Fprintf(w, "export %s=%s\n", name, quoteShell(v.Value))
+		}
+	}
+}
+
+// quoteShell return single quoted value for POSIX shell
+func quoteShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// writeVariablesTemplate print variables with go template
+func writeVariablesTemplate(w io.Writer, outputs []*VariableOutput, text string) error {
+	tmpl, err := template.New("show").Funcs(templateFuncs()).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	return tmpl.Execute(w, outputs)
+}
+
+// templateFuncs return helper functions available in template
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// json return JSON encoded value
+		"json": func(value any) (string, error) {
+			b, err := json.Marshal(value)
+			return string(b), err
+		},
+		// hcl return HCL quoted string
+		"hcl": func(value string) string {
+			return strings.TrimSpace(string(hclwrite.TokensForValue(cty.StringVal(value)).Bytes()))
+		},
+		// shell return single quoted string for POSIX shell
+		"shell": quoteShell,
+		// dotenv return double quoted string for dotenv file
+		"dotenv": quoteDotenv,
+		// mask return value of variable, masked if sensitive
+		"mask": func(v *VariableOutput) string {
+			return redactValue(v.Value, v.Sensitive)
+		},
+	}
+}
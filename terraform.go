@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -10,6 +11,9 @@ import (
 	"github.com/tidwall/gjson"
 )
 
+// listPageSize is page size to list resources of organization such as workspaces and variable sets
+const listPageSize = 100
+
 func updateTerraformCloudWorkspace(organization string, workspaceName string, workdir string) (string, string) {
 	srcByte, err := os.ReadFile(filepath.Join(workdir, ".terraform/terraform.tfstate"))
 	if err != nil {
@@ -38,35 +42,104 @@ func updateTerraformCloudWorkspace(organization string, workspaceName string, wo
 }
 
 func listVariableSetVariables(ctx context.Context, workspaceId string, VariableSets tfe.VariableSets, VariableSetVariables tfe.VariableSetVariables) ([]*tfe.Variable, error) {
+	variables, _, err := listVariableSetVariablesWithSource(ctx, workspaceId, VariableSets, VariableSetVariables)
+
+	return variables, err
+}
+
+// listVariableSetVariablesWithSource list variables of variable sets applied to workspace
+// with the name of variable set which each variable belongs to
+func listVariableSetVariablesWithSource(ctx context.Context, workspaceId string, VariableSets tfe.VariableSets, VariableSetVariables tfe.VariableSetVariables) ([]*tfe.Variable, map[*tfe.Variable]string, error) {
 	variables := make([]*tfe.Variable, 0)
+	sources := map[*tfe.Variable]string{}
 	s, err := VariableSets.ListForWorkspace(ctx, workspaceId, nil)
 	if err != nil {
 		log.Error().Err(err).Msgf("failed to list variable set in workspace %s", workspaceId)
-		return nil, err
+		return nil, nil, err
 	}
 
 	for setIndex := range s.Items {
 		variableList, err := VariableSetVariables.List(ctx, s.Items[setIndex].ID, nil)
 		if err != nil {
 			log.Error().Err(err).Msgf("failed to list VariableSetVariables ID: %s", s.Items[setIndex].ID)
-			return nil, err
+			return nil, nil, err
 		}
 
 		for variableListIndex := range variableList.Items {
-			variableSetVariable := variableList.Items[variableListIndex]
-			variable := &tfe.Variable{}
-
-			variable.ID = variableSetVariable.ID
-			variable.Key = variableSetVariable.Key
-			variable.Value = variableSetVariable.Value
-			variable.Description = variableSetVariable.Description
-			variable.Category = variableSetVariable.Category
-			variable.HCL = variableSetVariable.HCL
-			variable.Sensitive = variableSetVariable.Sensitive
+			variable := convertVariableSetVariable(variableList.Items[variableListIndex])
 
 			variables = append(variables, variable)
+			sources[variable] = s.Items[setIndex].Name
 		}
 	}
 
-	return variables, nil
+	return variables, sources, nil
+}
+
+// convertVariableSetVariable return workspace variable with the same attributes as variable set variable
+func convertVariableSetVariable(variableSetVariable *tfe.VariableSetVariable) *tfe.Variable {
+	variable := &tfe.Variable{}
+
+	variable.ID = variableSetVariable.ID
+	variable.Key = variableSetVariable.Key
+	variable.Value = variableSetVariable.Value
+	variable.Description = variableSetVariable.Description
+	variable.Category = variableSetVariable.Category
+	variable.HCL = variableSetVariable.HCL
+	variable.Sensitive = variableSetVariable.Sensitive
+
+	return variable
+}
+
+// findVariableSet return variable set of organization by name
+func findVariableSet(ctx context.Context, organization string, name string, VariableSets tfe.VariableSets) (*tfe.VariableSet, error) {
+	for page := 1; page != 0; {
+		s, err := VariableSets.List(ctx, organization, &tfe.VariableSetListOptions{
+			ListOptions: tfe.ListOptions{PageNumber: page, PageSize: listPageSize},
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("failed to list variable set in organization %s", organization)
+			return nil, err
+		}
+
+		for _, variableSet := range s.Items {
+			if variableSet.Name == name {
+				return variableSet, nil
+			}
+		}
+
+		page = nextPage(s.Pagination)
+	}
+
+	return nil, fmt.Errorf("variable set '%s' not found in organization %s", name, organization)
+}
+
+// nextPage return next page number, or 0 if there is no more page
+func nextPage(pagination *tfe.Pagination) int {
+	if pagination == nil {
+		return 0
+	}
+
+	return pagination.NextPage
+}
+
+// listSensitiveKeys return keys of sensitive terraform variables in workspace
+func listSensitiveKeys(ctx context.Context, workspaceId string, tfeVariables tfe.Variables) (map[string]bool, error) {
+	vars, err := tfeVariables.List(ctx, workspaceId, nil)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to list variables in workspace %s", workspaceId)
+		return nil, err
+	}
+
+	return sensitiveKeys(FilterEnv(vars.Items)), nil
+}
+
+// listVariableSetSensitiveKeys return keys of sensitive terraform variables in variable sets applied to workspace
+func listVariableSetSensitiveKeys(ctx context.Context, workspaceId string, VariableSets tfe.VariableSets, VariableSetVariables tfe.VariableSetVariables) (map[string]bool, error) {
+	vars, err := listVariableSetVariables(ctx, workspaceId, VariableSets, VariableSetVariables)
+	if err != nil {
+		return nil, err
+	}
+
+	return sensitiveKeys(FilterEnv(vars)), nil
 }
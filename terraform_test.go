@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -363,3 +364,97 @@ func TestListVariableSetVariables(t *testing.T) {
 		})
 	}
 }
+
+func TestFindVariableSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	VariableSets := mocks.NewMockVariableSets(ctrl)
+	VariableSets.EXPECT().
+		List(context.TODO(), "org-test", &tfe.VariableSetListOptions{ListOptions: tfe.ListOptions{PageNumber: 1, PageSize: listPageSize}}).
+		Return(&tfe.VariableSetList{
+			Items: []*tfe.VariableSet{
+				{ID: "varset-common", Name: "common"},
+				{ID: "varset-aws", Name: "aws-credentials"},
+			},
+			Pagination: &tfe.Pagination{CurrentPage: 1, NextPage: 2},
+		}, nil).
+		AnyTimes()
+	VariableSets.EXPECT().
+		List(context.TODO(), "org-test", &tfe.VariableSetListOptions{ListOptions: tfe.ListOptions{PageNumber: 2, PageSize: listPageSize}}).
+		Return(&tfe.VariableSetList{
+			Items: []*tfe.VariableSet{
+				{ID: "varset-gcp", Name: "gcp-credentials"},
+			},
+			Pagination: &tfe.Pagination{CurrentPage: 2, NextPage: 0},
+		}, nil).
+		AnyTimes()
+
+	cases := []struct {
+		name      string
+		setName   string
+		expectId  string
+		wantErr   bool
+		expectErr string
+	}{
+		{
+			name:     "find variable set by name",
+			setName:  "aws-credentials",
+			expectId: "varset-aws",
+		},
+		{
+			name:     "find variable set in next page",
+			setName:  "gcp-credentials",
+			expectId: "varset-gcp",
+		},
+		{
+			name:      "return error if variable set not exist",
+			setName:   "azure-credentials",
+			wantErr:   true,
+			expectErr: "variable set 'azure-credentials' not found",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := findVariableSet(context.TODO(), "org-test", tt.setName, VariableSets)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expect '%s' error, got no error", tt.expectErr)
+				} else if !strings.Contains(err.Error(), tt.expectErr) {
+					t.Errorf("expect '%s' error, got '%s'", tt.expectErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expect no error, got error: %v", err)
+			}
+			if actual.ID != tt.expectId {
+				t.Errorf("expect '%s', got '%s'", tt.expectId, actual.ID)
+			}
+		})
+	}
+}
+
+func TestListSensitiveKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVariables := mocks.NewMockVariables(ctrl)
+	mockVariables.EXPECT().
+		List(context.TODO(), "ws-test", nil).
+		Return(&tfe.VariableList{
+			Items: []*tfe.Variable{
+				{Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+				{Key: "db_port", Category: tfe.CategoryTerraform, Value: "5432"},
+				{Key: "AWS_SECRET_ACCESS_KEY", Category: tfe.CategoryEnv, Sensitive: true},
+			},
+		}, nil)
+
+	actual, err := listSensitiveKeys(context.TODO(), "ws-test", mockVariables)
+
+	if err != nil {
+		t.Errorf("expect no error, got error: %v", err)
+	}
+	expect := map[string]bool{"db_password": true}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect '%v', got '%v'", expect, actual)
+	}
+}
@@ -0,0 +1,22 @@
+variable "db_password" {
+  type      = string
+  sensitive = true
+}
+
+variable "db_port" {
+  type = number
+}
+
+variable "db_options" {
+  type = object({
+    ssl     = bool
+    timeout = number
+  })
+}
+
+variable "db_hosts" {
+  type = list(string)
+}
+
+variable "untyped" {
+}
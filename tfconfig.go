@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/rs/zerolog/log"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// VariableDeclaration is a variable block declared in terraform configuration
+type VariableDeclaration struct {
+	Name  string
+	Type  cty.Type
+	Range hcl.Range
+}
+
+var variableFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "variable",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+var variableBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "type",
+		},
+	},
+}
+
+// loadVariableDeclarations read variable blocks from terraform configuration files in dir
+// variables without type constraint are treated as cty.DynamicPseudoType
+func loadVariableDeclarations(dir string) (map[string]*VariableDeclaration, error) {
+	declarations := map[string]*VariableDeclaration{}
+
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+
+	p := hclparse.NewParser()
+	for _, filename := range filenames {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		file, diags := p.ParseHCL(src, filename)
+		if diags.HasErrors() {
+			log.Warn().Msgf("skip invalid terraform configuration %s: %s", filename, diags.Error())
+			continue
+		}
+
+		content, _, _ := file.Body.PartialContent(variableFileSchema)
+		for _, block := range content.Blocks {
+			declaration := &VariableDeclaration{
+				Name:  block.Labels[0],
+				Type:  cty.DynamicPseudoType,
+				Range: block.DefRange,
+			}
+
+			blockContent, _, _ := block.Body.PartialContent(variableBlockSchema)
+			if attr, ok := blockContent.Attributes["type"]; ok {
+				ty, diags := typeexpr.TypeConstraint(attr.Expr)
+				if !diags.HasErrors() {
+					declaration.Type = ty
+				}
+			}
+
+			declarations[declaration.Name] = declaration
+		}
+	}
+
+	return declarations, nil
+}
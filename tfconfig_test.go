@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestLoadVariableDeclarations(t *testing.T) {
+	declarations, err := loadVariableDeclarations("testdata/config")
+	if err != nil {
+		t.Fatalf("expect no error, got error: %v", err)
+	}
+
+	expect := map[string]string{
+		"db_password": "string",
+		"db_port":     "number",
+		"db_options":  "object",
+		"db_hosts":    "list of string",
+		"untyped":     "dynamic",
+	}
+	if len(declarations) != len(expect) {
+		t.Errorf("expect %d declarations, got %d", len(expect), len(declarations))
+	}
+	for name, typeName := range expect {
+		declaration, ok := declarations[name]
+		if !ok {
+			t.Errorf("expect variable '%s' declared", name)
+			continue
+		}
+		if actual := declaration.Type.FriendlyName(); actual != typeName {
+			t.Errorf("expect type of '%s' is '%s', got '%s'", name, typeName, actual)
+		}
+	}
+}
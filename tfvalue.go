@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rs/zerolog/log"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 func String(value cty.Value) string {
@@ -38,9 +39,18 @@ func String(value cty.Value) string {
 		return valString
 	}
 
+	// sort keys to render map in stable order
+	valueMap := value.AsValueMap()
+	keys := make([]string, 0, len(valueMap))
+	for key := range valueMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	first := true
 	valString := "{"
-	for key, val := range value.AsValueMap() {
+	for _, key := range keys {
+		val := valueMap[key]
 		if !first {
 			valString += ", "
 		}
@@ -82,6 +92,61 @@ func CtyValue(value string) cty.Value {
 	return val
 }
 
+// CompareValues return DIFF_UNCHANGED if values are equal,
+// DIFF_TYPE_CHANGED if values are equal only after converting primitive values into string, otherwise DIFF_CHANGED
+func CompareValues(old cty.Value, new cty.Value) string {
+	if valueEqual(old, new) {
+		return DIFF_UNCHANGED
+	}
+	if valueEqual(normalizeValue(old), normalizeValue(new)) {
+		return DIFF_TYPE_CHANGED
+	}
+
+	return DIFF_CHANGED
+}
+
+func valueEqual(a cty.Value, b cty.Value) bool {
+	if !a.IsWhollyKnown() || !b.IsWhollyKnown() {
+		return false
+	}
+	eq := a.Equals(b)
+
+	return eq.IsKnown() && eq.True()
+}
+
+// normalizeValue convert primitive values into string, list and set into tuple, and map into object recursively
+func normalizeValue(value cty.Value) cty.Value {
+	if value.IsNull() || !value.IsKnown() {
+		return value
+	}
+
+	ty := value.Type()
+	switch {
+	case ty.IsPrimitiveType():
+		str, err := convert.Convert(value, cty.String)
+		if err != nil {
+			return value
+		}
+		return str
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		elems := []cty.Value{}
+		for it := value.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			elems = append(elems, normalizeValue(elem))
+		}
+		return cty.TupleVal(elems)
+	case ty.IsMapType() || ty.IsObjectType():
+		attrs := map[string]cty.Value{}
+		for it := value.ElementIterator(); it.Next(); {
+			key, elem := it.Element()
+			attrs[key.AsString()] = normalizeValue(elem)
+		}
+		return cty.ObjectVal(attrs)
+	}
+
+	return value
+}
+
 func BuildVariableList(key string, value string) *tfe.VariableList {
 	vars := &tfe.VariableList{
 		Items: []*tfe.Variable{
@@ -95,7 +160,10 @@ func BuildVariableList(key string, value string) *tfe.VariableList {
 	return vars
 }
 
-func BuildHCLFile(remoteVars []*tfe.Variable, localFile []byte, filename string) (*hclwrite.File, error) {
+// BuildHCLFile merge remoteVars into localFile
+// descriptions are written as comments above newly added variables.
+// sensitive variables are written according to sensitiveOpt, or as comment if nil
+func BuildHCLFile(remoteVars []*tfe.Variable, localFile []byte, filename string, sensitiveOpt *SensitiveOption) (*hclwrite.File, error) {
 	f, diags := hclwrite.ParseConfig(localFile, filename, hcl.InitialPos)
 	if diags.HasErrors() {
 		log.Error().Msgf("failed to parse existing varfile: %s", diags.Error())
@@ -104,8 +172,14 @@ func BuildHCLFile(remoteVars []*tfe.Variable, localFile []byte, filename string)
 
 	rootBody := f.Body()
 	for _, v := range remoteVars {
+		// keep existing comments, and write description only for newly added variables
+		written := !v.Sensitive || sensitiveOpt.getStrategy() != SENSITIVE_OMIT
+		if v.Description != "" && written && rootBody.GetAttribute(v.Key) == nil {
+			rootBody.AppendUnstructuredTokens(generateDescriptionComment(v.Description))
+		}
+
 		if v.Sensitive {
-			rootBody.AppendUnstructuredTokens(generateComment(v.Key))
+			sensitiveOpt.writeSensitive(rootBody, v)
 			continue
 		}
 		if v.HCL {
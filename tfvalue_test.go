@@ -70,6 +70,11 @@ func TestString(t *testing.T) {
 			ctyValue: cty.ObjectVal(map[string]cty.Value{"key": cty.StringVal("value"), "key2": cty.ObjectVal(map[string]cty.Value{"key2key": cty.StringVal("nestedValue")})}),
 			expect:   []string{`{key = "value", key2 = {key2key = "nestedValue"}}`, `{key2 = {key2key = "nestedValue"}, key = "value"}`},
 		},
+		{
+			name:     "map keys in sorted order",
+			ctyValue: cty.ObjectVal(map[string]cty.Value{"c": cty.StringVal("3"), "a": cty.StringVal("1"), "b": cty.StringVal("2")}),
+			expect:   []string{`{a = "1", b = "2", c = "3"}`},
+		},
 	}
 
 	for _, tt := range cases {
@@ -167,6 +172,62 @@ func TestCtyValue(t *testing.T) {
 	}
 }
 
+func TestCompareValues(t *testing.T) {
+	cases := []struct {
+		name     string
+		oldValue cty.Value
+		newValue cty.Value
+		expect   string
+	}{
+		{
+			name:     "equal number with different format",
+			oldValue: CtyValue("3000"),
+			newValue: CtyValue("3000.0"),
+			expect:   DIFF_UNCHANGED,
+		},
+		{
+			name:     "equal object with different whitespace and order",
+			oldValue: CtyValue(`{ b = "2", a = "1" }`),
+			newValue: CtyValue("{\n  a = \"1\"\n  b = \"2\"\n}"),
+			expect:   DIFF_UNCHANGED,
+		},
+		{
+			name:     "string and number",
+			oldValue: cty.StringVal("3000"),
+			newValue: cty.NumberIntVal(3000),
+			expect:   DIFF_TYPE_CHANGED,
+		},
+		{
+			name:     "list and tuple of different primitive types",
+			oldValue: cty.ListVal([]cty.Value{cty.StringVal("1"), cty.StringVal("2")}),
+			newValue: cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.NumberIntVal(2)}),
+			expect:   DIFF_TYPE_CHANGED,
+		},
+		{
+			name:     "different value",
+			oldValue: cty.StringVal("3000"),
+			newValue: cty.NumberIntVal(3001),
+			expect:   DIFF_CHANGED,
+		},
+		{
+			name:     "null and value",
+			oldValue: cty.NullVal(cty.String),
+			newValue: cty.StringVal(""),
+			expect:   DIFF_CHANGED,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := CompareValues(tt.oldValue, tt.newValue)
+
+			if actual != tt.expect {
+				t.Errorf("expect '%s', got '%s'", tt.expect, actual)
+			}
+		})
+	}
+}
+
 func TestBuildVariableList(t *testing.T) {
 	cases := []struct {
 		name   string
@@ -238,7 +299,7 @@ func TestBuildHCLFile(t *testing.T) {
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			actual, err := BuildHCLFile(tt.vars, tt.filebody, tt.filename)
+			actual, err := BuildHCLFile(tt.vars, tt.filebody, tt.filename, nil)
 
 			if tt.wantErr {
 				if err == nil {
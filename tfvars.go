@@ -3,10 +3,12 @@ package main
 import (
 	"errors"
 	"os"
+	"strings"
 
 	tfe "github.com/hashicorp/go-tfe"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -49,6 +51,11 @@ func NewTfvarsFile(filename string) (*Tfvars, error) {
 		// if file not exist, treat as empty
 		vf.vardata = []byte("")
 	}
+	// encrypted file is decrypted only in memory
+	vf.vardata, err = decryptContents(filename, vf.vardata)
+	if err != nil {
+		return nil, err
+	}
 
 	err = vf.convertVarsfile()
 	if err != nil {
@@ -70,20 +77,72 @@ func (vf *Tfvars) convertVarsfile() error {
 		return errors.New(diags.Error())
 	}
 
+	descriptions := parseDescriptions(vf.vardata, vf.filename)
 	vf.vars = []*tfe.Variable{}
 	attrs, _ := f.Body.JustAttributes()
 	for _, attr := range SortAttributes(attrs) {
 		val, _ := attr.Expr.Value(nil)
 		vf.vars = append(vf.vars, &tfe.Variable{
-			Key:   attr.Name,
-			Value: String(val),
-			HCL:   !IsPrimitive(val),
+			Key:         attr.Name,
+			Value:       String(val),
+			Description: descriptions[attr.Name],
+			HCL:         !IsPrimitive(val),
 		})
 	}
 
 	return nil
 }
 
+// parseDescriptions read '#' comments directly above each attribute as its description
+// only comments in the format pull writes are read, and commented-out attributes are not descriptions
+func parseDescriptions(src []byte, filename string) map[string]string {
+	descriptions := map[string]string{}
+
+	f, diags := hclwrite.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return descriptions
+	}
+
+	for key, attr := range f.Body().Attributes() {
+		tokens := attr.BuildTokens(nil)
+		leading := 0
+		for leading < len(tokens) && tokens[leading].Type == hclsyntax.TokenComment {
+			leading++
+		}
+
+		lines := []string{}
+		for i := leading - 1; i >= 0; i-- {
+			line, ok := descriptionLine(string(tokens[i].Bytes))
+			if !ok {
+				break
+			}
+			lines = append([]string{line}, lines...)
+		}
+		if len(lines) != 0 {
+			descriptions[key] = strings.Join(lines, "\n")
+		}
+	}
+
+	return descriptions
+}
+
+// descriptionLine return text of comment written by generateDescriptionComment
+// return false if comment is in other format or is a commented-out attribute such as '# foo = "old"'
+func descriptionLine(comment string) (string, bool) {
+	comment = strings.TrimRight(comment, "\r\n")
+	if comment != "#" && !strings.HasPrefix(comment, "# ") {
+		return "", false
+	}
+	line := strings.TrimSpace(strings.TrimPrefix(comment, "#"))
+
+	f, diags := hclsyntax.ParseConfig([]byte(line), "", hcl.InitialPos)
+	if !diags.HasErrors() && len(f.Body.(*hclsyntax.Body).Attributes) != 0 {
+		return "", false
+	}
+
+	return line, true
+}
+
 // convertTfeVariables generate tfvars file from list of tfe.Varialbe
 func (vf *Tfvars) convertTfeVariables() error {
 	if vf.vars == nil {
@@ -132,3 +191,50 @@ func (vf *Tfvars) BuildHCLFileString() string {
 
 	return string(file.Bytes())
 }
+
+// values return parsed value of each attribute
+func (vf *Tfvars) values() map[string]cty.Value {
+	values := map[string]cty.Value{}
+
+	p := hclparse.NewParser()
+	f, diags := p.ParseHCL(vf.vardata, vf.filename)
+	if diags.HasErrors() {
+		return values
+	}
+	attrs, _ := f.Body.JustAttributes()
+	for name, attr := range attrs {
+		val, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			continue
+		}
+		values[name] = val
+	}
+
+	return values
+}
+
+// selectVariables return copy of tfvars file which contains only variables selected by filter
+func (vf *Tfvars) selectVariables(filter *VariableFilter) *Tfvars {
+	if filter == nil {
+		return vf
+	}
+
+	selected := &Tfvars{
+		filename: vf.filename,
+		vardata:  vf.vardata,
+		vars:     filter.Filter(vf.vars),
+	}
+
+	f, diags := hclwrite.ParseConfig(vf.vardata, vf.filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return selected
+	}
+	for _, v := range vf.vars {
+		if !filter.Match(v) {
+			f.Body().RemoveAttribute(v.Key)
+		}
+	}
+	selected.vardata = f.Bytes()
+
+	return selected
+}
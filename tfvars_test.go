@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
@@ -166,3 +167,27 @@ func TestTfvars_BuildHCLFileString(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDescriptions(t *testing.T) {
+	src, err := os.ReadFile("testdata/descriptions.tfvars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{
+		"environment": "Environment name\nused for tagging",
+	}
+
+	actual := parseDescriptions(src, "testdata/descriptions.tfvars")
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect '%v', got '%v'", expect, actual)
+	}
+}
+
+func TestParseDescriptionsCommentedOutAttribute(t *testing.T) {
+	src := []byte("# foo = \"old\"\nfoo = \"new\"\n\n# Bar value\n# bar = \"old\"\nbar = \"new\"\n")
+
+	actual := parseDescriptions(src, "terraform.tfvars")
+	if len(actual) != 0 {
+		t.Errorf("expect no descriptions, got '%v'", actual)
+	}
+}